@@ -0,0 +1,416 @@
+package gzap
+
+import (
+	"crypto/tls"
+	"encoding/binary"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	defaultQueueSize     = 1024
+	defaultSpillMaxBytes = 64 << 20 // 64MiB
+	spillSegmentMaxBytes = 8 << 20  // 8MiB per segment before rotating
+)
+
+const (
+	bufferedMinBackoff = 250 * time.Millisecond
+	bufferedMaxBackoff = 30 * time.Second
+)
+
+// GraylogSinkStats is a point-in-time snapshot of a BufferedGraylogSink's
+// Prometheus-style counters and gauges.
+type GraylogSinkStats struct {
+	DroppedTotal    uint64
+	QueueDepth      uint64
+	ReconnectsTotal uint64
+}
+
+// BufferedGraylogSink sits between the zap core and the Graylog
+// transport. Writes are pushed onto an in-memory ring buffer and handed
+// off to a background flusher goroutine so a slow or unreachable Graylog
+// endpoint never blocks the calling goroutine. When the ring buffer is
+// full, entries spill to a bounded, rotating directory on disk instead of
+// being dropped outright, and are replayed the next time the sink starts.
+type BufferedGraylogSink struct {
+	cfg       Config
+	tlsConfig *tls.Config
+
+	queue chan []byte
+
+	spillDir      string
+	spillMaxBytes int64
+	spillMu       sync.Mutex
+	replaying     bool
+
+	conn    net.Conn
+	connMu  sync.Mutex
+	backoff time.Duration
+
+	droppedTotal    uint64
+	reconnectsTotal uint64
+
+	closeOnce sync.Once
+	closeCh   chan struct{}
+	wg        sync.WaitGroup
+}
+
+// NewBufferedGraylogSink builds a BufferedGraylogSink sized from
+// CfgConfig.QueueSize, replays any spilled segments left over from a
+// previous run, and starts the background flusher. tlsConfig is used to
+// dial Graylog when cfg.useTLS() is true; passing nil builds one from
+// cfg's TLSConfig knobs (CA bundle, client certificate, SNI, ...).
+func NewBufferedGraylogSink(cfg Config, tlsConfig *tls.Config) (*BufferedGraylogSink, error) {
+	queueSize := cfg.getGraylogQueueSize()
+	if queueSize == 0 {
+		queueSize = defaultQueueSize
+	}
+
+	if tlsConfig == nil && cfg.useTLS() {
+		built, err := buildGraylogTLSConfig(cfg)
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig = built
+	}
+
+	s := &BufferedGraylogSink{
+		cfg:           cfg,
+		tlsConfig:     tlsConfig,
+		queue:         make(chan []byte, queueSize),
+		spillDir:      cfg.getGraylogSpillDir(),
+		spillMaxBytes: cfg.getGraylogSpillMaxBytes(),
+		backoff:       bufferedMinBackoff,
+		closeCh:       make(chan struct{}),
+	}
+
+	if s.spillDir != "" {
+		if err := os.MkdirAll(s.spillDir, 0o755); err != nil {
+			return nil, fmt.Errorf("gzap: could not create spill dir %q: %w", s.spillDir, err)
+		}
+
+		if err := s.replaySpill(); err != nil {
+			return nil, err
+		}
+	}
+
+	s.wg.Add(1)
+	go s.flushLoop()
+
+	return s, nil
+}
+
+// Write enqueues p without blocking, ignoring the error enqueue returns
+// on drop; it exists so BufferedGraylogSink can also serve as a plain
+// zapcore.WriteSyncer for callers that don't need per-message framing.
+func (s *BufferedGraylogSink) Write(p []byte) (int, error) {
+	_ = s.enqueue(append([]byte(nil), p...))
+	return len(p), nil
+}
+
+// enqueue pushes buf onto the in-memory ring buffer without blocking. If
+// the queue is full, buf spills to disk when a spill directory is
+// configured; otherwise it is dropped and counted in
+// Stats().DroppedTotal. It returns an error when buf could not be
+// queued, spilled, or spilled to a place a later reload will replay -
+// i.e. it was actually lost.
+func (s *BufferedGraylogSink) enqueue(buf []byte) error {
+	select {
+	case s.queue <- buf:
+		return nil
+	default:
+	}
+
+	if s.spillDir != "" {
+		if err := s.spill(buf); err != nil {
+			atomic.AddUint64(&s.droppedTotal, 1)
+			return err
+		}
+		return nil
+	}
+
+	atomic.AddUint64(&s.droppedTotal, 1)
+	return fmt.Errorf("gzap: graylog sink queue is full and no spill dir is configured")
+}
+
+// Sync is a no-op; the flusher writes through to the network on its own
+// schedule and there is nothing further to flush synchronously.
+func (s *BufferedGraylogSink) Sync() error {
+	return nil
+}
+
+// Close stops the background flusher and closes the underlying
+// connection. Entries still queued are dropped (or spilled, if a spill
+// directory is configured) rather than blocking shutdown.
+func (s *BufferedGraylogSink) Close() error {
+	s.closeOnce.Do(func() {
+		close(s.closeCh)
+	})
+	s.wg.Wait()
+
+	for drained := false; !drained; {
+		select {
+		case buf := <-s.queue:
+			if s.spillDir == "" || s.spill(buf) != nil {
+				atomic.AddUint64(&s.droppedTotal, 1)
+			}
+		default:
+			drained = true
+		}
+	}
+
+	s.connMu.Lock()
+	defer s.connMu.Unlock()
+	if s.conn != nil {
+		err := s.conn.Close()
+		s.conn = nil
+		return err
+	}
+
+	return nil
+}
+
+// Stats returns a snapshot of the sink's counters and current queue
+// depth, suitable for exporting as Prometheus gzap_dropped_total,
+// gzap_queue_depth, and gzap_reconnects_total metrics.
+func (s *BufferedGraylogSink) Stats() GraylogSinkStats {
+	return GraylogSinkStats{
+		DroppedTotal:    atomic.LoadUint64(&s.droppedTotal),
+		QueueDepth:      uint64(len(s.queue)),
+		ReconnectsTotal: atomic.LoadUint64(&s.reconnectsTotal),
+	}
+}
+
+func (s *BufferedGraylogSink) flushLoop() {
+	defer s.wg.Done()
+
+	for {
+		select {
+		case <-s.closeCh:
+			return
+		case buf := <-s.queue:
+			if err := s.send(buf); err != nil {
+				if s.spillDir == "" || s.spill(buf) != nil {
+					atomic.AddUint64(&s.droppedTotal, 1)
+				}
+			}
+		}
+	}
+}
+
+func (s *BufferedGraylogSink) send(buf []byte) error {
+	conn, err := s.dial()
+	if err != nil {
+		time.Sleep(s.backoff)
+		s.backoff = nextBufferedBackoff(s.backoff)
+		return err
+	}
+
+	if _, err := conn.Write(buf); err != nil {
+		s.connMu.Lock()
+		if s.conn != nil {
+			s.conn.Close()
+			s.conn = nil
+		}
+		s.connMu.Unlock()
+
+		time.Sleep(s.backoff)
+		s.backoff = nextBufferedBackoff(s.backoff)
+		return err
+	}
+
+	s.backoff = bufferedMinBackoff
+	return nil
+}
+
+func (s *BufferedGraylogSink) dial() (net.Conn, error) {
+	s.connMu.Lock()
+	defer s.connMu.Unlock()
+
+	if s.conn != nil {
+		return s.conn, nil
+	}
+
+	address := fmt.Sprintf("%s:%d", s.cfg.getGraylogHost(), s.cfg.getGraylogPort())
+	dialer := &net.Dialer{Timeout: s.cfg.getGraylogTLSTimeout()}
+
+	var conn net.Conn
+	var err error
+	if s.cfg.useTLS() {
+		conn, err = tls.DialWithDialer(dialer, "tcp", address, s.tlsConfig)
+	} else {
+		conn, err = dialer.Dial("udp", address)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("gzap: could not dial graylog at %s: %w", address, err)
+	}
+
+	s.conn = conn
+	atomic.AddUint64(&s.reconnectsTotal, 1)
+
+	return conn, nil
+}
+
+func nextBufferedBackoff(cur time.Duration) time.Duration {
+	next := cur * 2
+	if next > bufferedMaxBackoff {
+		return bufferedMaxBackoff
+	}
+	return next
+}
+
+// spill appends a length-prefixed record to the current spill segment,
+// rotating to a new segment once the current one exceeds
+// spillSegmentMaxBytes, and trimming the oldest segments once the
+// directory exceeds spillMaxBytes. While a replay is in progress it
+// always creates a brand new segment instead of reusing the newest one,
+// since that segment may be the very file replaySegment is mid-read on
+// (or one already deleted earlier in this replay pass).
+func (s *BufferedGraylogSink) spill(buf []byte) error {
+	s.spillMu.Lock()
+	defer s.spillMu.Unlock()
+
+	path := filepath.Join(s.spillDir, fmt.Sprintf("%020d.seg", time.Now().UnixNano()))
+	if !s.replaying {
+		segments, err := s.spillSegments()
+		if err != nil {
+			return err
+		}
+
+		if len(segments) > 0 {
+			last := segments[len(segments)-1]
+			if info, err := os.Stat(last); err == nil && info.Size() < spillSegmentMaxBytes {
+				path = last
+			}
+		}
+	}
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("gzap: could not open spill segment %q: %w", path, err)
+	}
+	defer f.Close()
+
+	if err := writeSpillRecord(f, buf); err != nil {
+		return err
+	}
+
+	return s.enforceSpillBound()
+}
+
+func writeSpillRecord(f *os.File, buf []byte) error {
+	var lenPrefix [4]byte
+	binary.BigEndian.PutUint32(lenPrefix[:], uint32(len(buf)))
+
+	if _, err := f.Write(lenPrefix[:]); err != nil {
+		return fmt.Errorf("gzap: could not write spill record length: %w", err)
+	}
+	if _, err := f.Write(buf); err != nil {
+		return fmt.Errorf("gzap: could not write spill record: %w", err)
+	}
+
+	return nil
+}
+
+func (s *BufferedGraylogSink) spillSegments() ([]string, error) {
+	entries, err := os.ReadDir(s.spillDir)
+	if err != nil {
+		return nil, fmt.Errorf("gzap: could not list spill dir %q: %w", s.spillDir, err)
+	}
+
+	var segments []string
+	for _, entry := range entries {
+		if !entry.IsDir() && filepath.Ext(entry.Name()) == ".seg" {
+			segments = append(segments, filepath.Join(s.spillDir, entry.Name()))
+		}
+	}
+
+	sort.Strings(segments)
+	return segments, nil
+}
+
+// enforceSpillBound deletes the oldest spill segments until the
+// directory's total size is back under spillMaxBytes.
+func (s *BufferedGraylogSink) enforceSpillBound() error {
+	segments, err := s.spillSegments()
+	if err != nil {
+		return err
+	}
+
+	var total int64
+	sizes := make([]int64, len(segments))
+	for i, segment := range segments {
+		info, err := os.Stat(segment)
+		if err != nil {
+			continue
+		}
+		sizes[i] = info.Size()
+		total += info.Size()
+	}
+
+	for i := 0; total > s.spillMaxBytes && i < len(segments); i++ {
+		if err := os.Remove(segments[i]); err != nil {
+			continue
+		}
+		total -= sizes[i]
+	}
+
+	return nil
+}
+
+// replaySpill reads every spill segment in creation order and hands its
+// records to enqueue, which re-queues them in memory or, if the queue is
+// still full, spills them again into a fresh segment for the next
+// replay. A segment is only removed once every one of its records has
+// been accounted for one way or the other, so a restart recovering more
+// volume than QueueSize holds never loses data outright.
+func (s *BufferedGraylogSink) replaySpill() error {
+	s.replaying = true
+	defer func() { s.replaying = false }()
+
+	segments, err := s.spillSegments()
+	if err != nil {
+		return err
+	}
+
+	for _, segment := range segments {
+		if err := s.replaySegment(segment); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *BufferedGraylogSink) replaySegment(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("gzap: could not read spill segment %q: %w", path, err)
+	}
+
+	for offset := 0; offset < len(data); {
+		if offset+4 > len(data) {
+			break
+		}
+		recLen := int(binary.BigEndian.Uint32(data[offset : offset+4]))
+		offset += 4
+
+		if offset+recLen > len(data) {
+			break
+		}
+		record := data[offset : offset+recLen]
+		offset += recLen
+
+		if err := s.enqueue(append([]byte(nil), record...)); err != nil {
+			return fmt.Errorf("gzap: could not requeue record from spill segment %q: %w", path, err)
+		}
+	}
+
+	return os.Remove(path)
+}
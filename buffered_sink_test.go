@@ -0,0 +1,118 @@
+package gzap
+
+import (
+	"sync/atomic"
+	"testing"
+)
+
+func newTestBufferedSink(t *testing.T, queueSize int, spillDir string) *BufferedGraylogSink {
+	t.Helper()
+	return &BufferedGraylogSink{
+		queue:         make(chan []byte, queueSize),
+		spillDir:      spillDir,
+		spillMaxBytes: defaultSpillMaxBytes,
+		closeCh:       make(chan struct{}),
+	}
+}
+
+func TestEnqueueSpillsWhenQueueFull(t *testing.T) {
+	s := newTestBufferedSink(t, 1, t.TempDir())
+
+	if err := s.enqueue([]byte("first")); err != nil {
+		t.Fatalf("enqueue(first) = %v, want nil", err)
+	}
+	if err := s.enqueue([]byte("second")); err != nil {
+		t.Fatalf("enqueue(second) into a full queue with a spill dir = %v, want nil (spilled, not dropped)", err)
+	}
+
+	if got := atomic.LoadUint64(&s.droppedTotal); got != 0 {
+		t.Errorf("droppedTotal = %d, want 0: a successful spill is not a drop", got)
+	}
+
+	segments, err := s.spillSegments()
+	if err != nil {
+		t.Fatalf("spillSegments: %v", err)
+	}
+	if len(segments) != 1 {
+		t.Fatalf("spillSegments = %v, want exactly one segment", segments)
+	}
+}
+
+func TestEnqueueDropsWhenQueueFullAndNoSpillDir(t *testing.T) {
+	s := newTestBufferedSink(t, 1, "")
+
+	if err := s.enqueue([]byte("first")); err != nil {
+		t.Fatalf("enqueue(first) = %v, want nil", err)
+	}
+	if err := s.enqueue([]byte("second")); err == nil {
+		t.Fatal("enqueue into a full queue with no spill dir = nil, want an error")
+	}
+
+	if got := atomic.LoadUint64(&s.droppedTotal); got != 1 {
+		t.Errorf("droppedTotal = %d, want 1", got)
+	}
+}
+
+func TestReplaySpillRequeuesRecords(t *testing.T) {
+	dir := t.TempDir()
+
+	writer := newTestBufferedSink(t, 1, dir)
+	if err := writer.enqueue([]byte("a")); err != nil {
+		t.Fatalf("enqueue(a) = %v", err)
+	}
+	for _, rec := range [][]byte{[]byte("b"), []byte("c")} {
+		if err := writer.enqueue(rec); err != nil {
+			t.Fatalf("enqueue(%s) = %v", rec, err)
+		}
+	}
+
+	reader := newTestBufferedSink(t, 10, dir)
+	if err := reader.replaySpill(); err != nil {
+		t.Fatalf("replaySpill: %v", err)
+	}
+
+	if got := len(reader.queue); got != 2 {
+		t.Fatalf("queue depth after replay = %d, want 2 (the two spilled records)", got)
+	}
+
+	segments, err := reader.spillSegments()
+	if err != nil {
+		t.Fatalf("spillSegments: %v", err)
+	}
+	if len(segments) != 0 {
+		t.Errorf("spillSegments after a successful replay = %v, want none left behind", segments)
+	}
+}
+
+func TestReplaySpillReSpillsWhenQueueStillFull(t *testing.T) {
+	dir := t.TempDir()
+
+	// Queue capacity 1: "a" fits in memory and is never spilled; "b" and
+	// "c" both overflow to the same on-disk segment.
+	writer := newTestBufferedSink(t, 1, dir)
+	for _, rec := range [][]byte{[]byte("a"), []byte("b"), []byte("c")} {
+		if err := writer.enqueue(rec); err != nil {
+			t.Fatalf("enqueue(%s) = %v", rec, err)
+		}
+	}
+
+	// A reader restarting with the same small queue capacity can only
+	// requeue one of the two spilled records ("b"); "c" must come back
+	// out as a freshly spilled segment instead of being lost.
+	reader := newTestBufferedSink(t, 1, dir)
+	if err := reader.replaySpill(); err != nil {
+		t.Fatalf("replaySpill: %v", err)
+	}
+
+	if got := len(reader.queue); got != 1 {
+		t.Fatalf("queue depth after replay = %d, want 1 (queue can only hold one)", got)
+	}
+
+	segments, err := reader.spillSegments()
+	if err != nil {
+		t.Fatalf("spillSegments: %v", err)
+	}
+	if len(segments) != 1 {
+		t.Fatalf("spillSegments after replay overflow = %v, want the overflow record re-spilled, not lost", segments)
+	}
+}
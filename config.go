@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 
 	graylog "github.com/Devatoria/go-graylog"
@@ -27,6 +28,30 @@ type Config interface {
 	getIsTestEnv() bool
 	useTLS() bool
 	useColoredConsolelogs() bool
+	useSyslog() bool
+	getSyslogNetwork() string
+	getSyslogAddress() string
+	getSyslogFacility() string
+	getSyslogAppTag() string
+	getSyslogHostname() string
+	getSyslogUseRFC5424() bool
+	getSyslogSkipInsecureSkipVerify() bool
+	getSyslogTLSCAFile() string
+	getSyslogTLSCertFile() string
+	getSyslogTLSKeyFile() string
+	getSyslogTLSServerName() string
+	getSyslogTLSMinVersion() uint16
+	getSyslogTLSCipherSuites() []uint16
+	getGraylogQueueSize() uint
+	getGraylogSpillDir() string
+	getGraylogSpillMaxBytes() int64
+	getGraylogTLSCAFile() string
+	getGraylogTLSCertFile() string
+	getGraylogTLSKeyFile() string
+	getGraylogTLSServerName() string
+	getGraylogTLSMinVersion() uint16
+	getGraylogTLSCipherSuites() []uint16
+	getSinkConfigs() []SinkConfig
 }
 
 // EnvConfig represents all the logger configurations available
@@ -172,17 +197,210 @@ func (e *EnvConfig) useColoredConsolelogs() bool {
 	return false
 }
 
+func (e *EnvConfig) useSyslog() bool {
+	return os.Getenv("SYSLOG_ADDRESS") != ""
+}
+
+func (e *EnvConfig) getSyslogNetwork() string {
+	network := os.Getenv("SYSLOG_NETWORK")
+	if network == "" {
+		return "udp"
+	}
+
+	return network
+}
+
+func (e *EnvConfig) getSyslogAddress() string {
+	return os.Getenv("SYSLOG_ADDRESS")
+}
+
+func (e *EnvConfig) getSyslogFacility() string {
+	facility := os.Getenv("SYSLOG_FACILITY")
+	if facility == "" {
+		return "user"
+	}
+
+	return facility
+}
+
+func (e *EnvConfig) getSyslogAppTag() string {
+	tag := os.Getenv("SYSLOG_APP_TAG")
+	if tag == "" {
+		return e.getGraylogAppName()
+	}
+
+	return tag
+}
+
+func (e *EnvConfig) getSyslogHostname() string {
+	hostname := os.Getenv("SYSLOG_HOSTNAME")
+	if hostname != "" {
+		return hostname
+	}
+
+	host, err := os.Hostname()
+	if err != nil {
+		return "unknown"
+	}
+
+	return host
+}
+
+func (e *EnvConfig) getSyslogUseRFC5424() bool {
+	return os.Getenv("SYSLOG_USE_BSD_FRAMING") != "true"
+}
+
+func (e *EnvConfig) getSyslogSkipInsecureSkipVerify() bool {
+	return os.Getenv("SYSLOG_SKIP_TLS_VERIFY") == "true"
+}
+
+func (e *EnvConfig) getSyslogTLSCAFile() string {
+	return os.Getenv("SYSLOG_TLS_CA_FILE")
+}
+
+func (e *EnvConfig) getSyslogTLSCertFile() string {
+	return os.Getenv("SYSLOG_TLS_CERT_FILE")
+}
+
+func (e *EnvConfig) getSyslogTLSKeyFile() string {
+	return os.Getenv("SYSLOG_TLS_KEY_FILE")
+}
+
+func (e *EnvConfig) getSyslogTLSServerName() string {
+	serverName := os.Getenv("SYSLOG_TLS_SERVER_NAME")
+	if serverName == "" {
+		return strings.Split(e.getSyslogAddress(), ":")[0]
+	}
+
+	return serverName
+}
+
+func (e *EnvConfig) getSyslogTLSMinVersion() uint16 {
+	return parseTLSVersion(os.Getenv("SYSLOG_TLS_MIN_VERSION"))
+}
+
+func (e *EnvConfig) getSyslogTLSCipherSuites() []uint16 {
+	return parseTLSCipherSuites(os.Getenv("SYSLOG_TLS_CIPHER_SUITES"))
+}
+
+func (e *EnvConfig) getGraylogQueueSize() uint {
+	s := os.Getenv("GRAYLOG_QUEUE_SIZE")
+	if s == "" {
+		return defaultQueueSize
+	}
+
+	size, err := strconv.ParseUint(s, 10, 32)
+	if err != nil {
+		panic(fmt.Errorf("could not properly parse GRAYLOG_QUEUE_SIZE: %s", s))
+	}
+
+	return uint(size)
+}
+
+func (e *EnvConfig) getGraylogSpillDir() string {
+	return os.Getenv("GRAYLOG_SPILL_DIR")
+}
+
+func (e *EnvConfig) getGraylogSpillMaxBytes() int64 {
+	s := os.Getenv("GRAYLOG_SPILL_MAX_BYTES")
+	if s == "" {
+		return defaultSpillMaxBytes
+	}
+
+	max, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		panic(fmt.Errorf("could not properly parse GRAYLOG_SPILL_MAX_BYTES: %s", s))
+	}
+
+	return max
+}
+
+func (e *EnvConfig) getGraylogTLSCAFile() string {
+	return os.Getenv("GRAYLOG_TLS_CA_FILE")
+}
+
+func (e *EnvConfig) getGraylogTLSCertFile() string {
+	return os.Getenv("GRAYLOG_TLS_CERT_FILE")
+}
+
+func (e *EnvConfig) getGraylogTLSKeyFile() string {
+	return os.Getenv("GRAYLOG_TLS_KEY_FILE")
+}
+
+func (e *EnvConfig) getGraylogTLSServerName() string {
+	serverName := os.Getenv("GRAYLOG_TLS_SERVER_NAME")
+	if serverName == "" {
+		return e.getGraylogHost()
+	}
+
+	return serverName
+}
+
+func (e *EnvConfig) getGraylogTLSMinVersion() uint16 {
+	return parseTLSVersion(os.Getenv("GRAYLOG_TLS_MIN_VERSION"))
+}
+
+func (e *EnvConfig) getGraylogTLSCipherSuites() []uint16 {
+	return parseTLSCipherSuites(os.Getenv("GRAYLOG_TLS_CIPHER_SUITES"))
+}
+
+// getSinkConfigs returns the default sink list for an env-configured
+// logger: console and Graylog always, plus syslog when SYSLOG_ADDRESS is
+// set. Env vars have no way to express per-sink options or min-levels,
+// so every entry uses the zero value for both.
+func (e *EnvConfig) getSinkConfigs() []SinkConfig {
+	sinks := []SinkConfig{{Driver: "console"}, {Driver: "graylog"}}
+	if e.useSyslog() {
+		sinks = append(sinks, SinkConfig{Driver: "syslog"})
+	}
+
+	return sinks
+}
+
 // CfgConfig implement Config interface from config struct
 type CfgConfig struct {
-	EnableJSONFormatter bool   `json:"enable_json_formatter" yaml:"enable_json_formatter"`
-	AppName             string `json:"app_name" yaml:"app_name"`
-	EnvName             string `json:"env_name" yaml:"env_name"`
-	HanlderType         string `json:"handler_name" yaml:"handler_name"`
-	Host                string `json:"host" yaml:"host"`
-	UDPPort             uint   `json:"udp_port" yaml:"udp_port"`
-	TLSPort             uint   `json:"tls_port" yaml:"tls_port"`
-	TLSTimeoutSeconds   string `json:"tls_timeout_seconds" yaml:"tls_timeout_seconds"`
-	LogLevel            uint   `json:"log_level" yaml:"log_level"`
+	EnableJSONFormatter bool         `json:"enable_json_formatter" yaml:"enable_json_formatter" toml:"enable_json_formatter"`
+	AppName             string       `json:"app_name" yaml:"app_name" toml:"app_name"`
+	EnvName             string       `json:"env_name" yaml:"env_name" toml:"env_name"`
+	HanlderType         string       `json:"handler_name" yaml:"handler_name" toml:"handler_name"`
+	Host                string       `json:"host" yaml:"host" toml:"host"`
+	UDPPort             uint         `json:"udp_port" yaml:"udp_port" toml:"udp_port"`
+	TLSPort             uint         `json:"tls_port" yaml:"tls_port" toml:"tls_port"`
+	TLSTimeoutSeconds   string       `json:"tls_timeout_seconds" yaml:"tls_timeout_seconds" toml:"tls_timeout_seconds"`
+	LogLevel            uint         `json:"log_level" yaml:"log_level" toml:"log_level"`
+	Syslog              SyslogConfig `json:"syslog" yaml:"syslog" toml:"syslog"`
+	QueueSize           uint         `json:"queue_size" yaml:"queue_size" toml:"queue_size"`
+	SpillDir            string       `json:"spill_dir" yaml:"spill_dir" toml:"spill_dir"`
+	SpillMaxBytes       int64        `json:"spill_max_bytes" yaml:"spill_max_bytes" toml:"spill_max_bytes"`
+	TLS                 TLSConfig    `json:"tls" yaml:"tls" toml:"tls"`
+	Sinks               []SinkConfig `json:"sinks" yaml:"sinks" toml:"sinks"`
+}
+
+// TLSConfig holds the certificate material and negotiation parameters
+// used when dialing Graylog over tcp+tls (CfgConfig.TLS) or a tcp+tls
+// syslog endpoint (SyslogConfig.TLS), including mutual TLS via
+// CertFile/KeyFile and a custom CA bundle via CAFile.
+type TLSConfig struct {
+	CAFile             string   `json:"ca_file" yaml:"ca_file" toml:"ca_file"`
+	CertFile           string   `json:"cert_file" yaml:"cert_file" toml:"cert_file"`
+	KeyFile            string   `json:"key_file" yaml:"key_file" toml:"key_file"`
+	ServerName         string   `json:"server_name" yaml:"server_name" toml:"server_name"`
+	MinVersion         string   `json:"min_version" yaml:"min_version" toml:"min_version"`
+	CipherSuites       []string `json:"cipher_suites" yaml:"cipher_suites" toml:"cipher_suites"`
+	InsecureSkipVerify bool     `json:"insecure_skip_verify" yaml:"insecure_skip_verify" toml:"insecure_skip_verify"`
+}
+
+// SyslogConfig holds the settings needed to forward zap entries to a
+// syslog endpoint (rsyslog, journald, or any RFC 5424 collector).
+type SyslogConfig struct {
+	Enabled    bool      `json:"enabled" yaml:"enabled" toml:"enabled"`
+	Network    string    `json:"network" yaml:"network" toml:"network"` // udp, tcp, or tcp+tls
+	Address    string    `json:"address" yaml:"address" toml:"address"`
+	Facility   string    `json:"facility" yaml:"facility" toml:"facility"`
+	AppTag     string    `json:"app_tag" yaml:"app_tag" toml:"app_tag"`
+	Hostname   string    `json:"hostname" yaml:"hostname" toml:"hostname"`
+	UseRFC5424 bool      `json:"use_rfc5424" yaml:"use_rfc5424" toml:"use_rfc5424"`
+	TLS        TLSConfig `json:"tls" yaml:"tls" toml:"tls"` // only used when Network is tcp+tls; independent of the Graylog sink's TLS
 }
 
 func NewDefaultCfgConfig() *CfgConfig {
@@ -196,6 +414,14 @@ func NewDefaultCfgConfig() *CfgConfig {
 		TLSPort:             12001,
 		TLSTimeoutSeconds:   "3",
 		LogLevel:            4,
+		Syslog: SyslogConfig{
+			Enabled:    false,
+			Network:    "udp",
+			Facility:   "user",
+			UseRFC5424: true,
+		},
+		QueueSize:     defaultQueueSize,
+		SpillMaxBytes: defaultSpillMaxBytes,
 	}
 	return cfg
 }
@@ -270,7 +496,7 @@ func (e *CfgConfig) getGraylogLogEnvName() string {
 }
 
 func (e *CfgConfig) getGraylogSkipInsecureSkipVerify() bool {
-	return false
+	return e.TLS.InsecureSkipVerify
 }
 
 func (e *CfgConfig) getIsTestEnv() bool {
@@ -298,3 +524,148 @@ func (e *CfgConfig) useTLS() bool {
 func (e *CfgConfig) useColoredConsolelogs() bool {
 	return false
 }
+
+func (e *CfgConfig) useSyslog() bool {
+	return e.Syslog.Enabled
+}
+
+func (e *CfgConfig) getSyslogNetwork() string {
+	if e.Syslog.Network == "" {
+		return "udp"
+	}
+
+	return e.Syslog.Network
+}
+
+func (e *CfgConfig) getSyslogAddress() string {
+	return e.Syslog.Address
+}
+
+func (e *CfgConfig) getSyslogFacility() string {
+	if e.Syslog.Facility == "" {
+		return "user"
+	}
+
+	return e.Syslog.Facility
+}
+
+func (e *CfgConfig) getSyslogAppTag() string {
+	if e.Syslog.AppTag == "" {
+		return e.AppName
+	}
+
+	return e.Syslog.AppTag
+}
+
+func (e *CfgConfig) getSyslogHostname() string {
+	if e.Syslog.Hostname != "" {
+		return e.Syslog.Hostname
+	}
+
+	host, err := os.Hostname()
+	if err != nil {
+		return "unknown"
+	}
+
+	return host
+}
+
+func (e *CfgConfig) getSyslogUseRFC5424() bool {
+	return e.Syslog.UseRFC5424
+}
+
+func (e *CfgConfig) getSyslogSkipInsecureSkipVerify() bool {
+	return e.Syslog.TLS.InsecureSkipVerify
+}
+
+func (e *CfgConfig) getSyslogTLSCAFile() string {
+	return e.Syslog.TLS.CAFile
+}
+
+func (e *CfgConfig) getSyslogTLSCertFile() string {
+	return e.Syslog.TLS.CertFile
+}
+
+func (e *CfgConfig) getSyslogTLSKeyFile() string {
+	return e.Syslog.TLS.KeyFile
+}
+
+func (e *CfgConfig) getSyslogTLSServerName() string {
+	if e.Syslog.TLS.ServerName == "" {
+		return strings.Split(e.Syslog.Address, ":")[0]
+	}
+
+	return e.Syslog.TLS.ServerName
+}
+
+func (e *CfgConfig) getSyslogTLSMinVersion() uint16 {
+	return parseTLSVersion(e.Syslog.TLS.MinVersion)
+}
+
+func (e *CfgConfig) getSyslogTLSCipherSuites() []uint16 {
+	return parseTLSCipherSuites(strings.Join(e.Syslog.TLS.CipherSuites, ","))
+}
+
+func (e *CfgConfig) getGraylogQueueSize() uint {
+	if e.QueueSize == 0 {
+		return defaultQueueSize
+	}
+
+	return e.QueueSize
+}
+
+func (e *CfgConfig) getGraylogSpillDir() string {
+	return e.SpillDir
+}
+
+func (e *CfgConfig) getGraylogSpillMaxBytes() int64 {
+	if e.SpillMaxBytes == 0 {
+		return defaultSpillMaxBytes
+	}
+
+	return e.SpillMaxBytes
+}
+
+func (e *CfgConfig) getGraylogTLSCAFile() string {
+	return e.TLS.CAFile
+}
+
+func (e *CfgConfig) getGraylogTLSCertFile() string {
+	return e.TLS.CertFile
+}
+
+func (e *CfgConfig) getGraylogTLSKeyFile() string {
+	return e.TLS.KeyFile
+}
+
+func (e *CfgConfig) getGraylogTLSServerName() string {
+	if e.TLS.ServerName == "" {
+		return e.Host
+	}
+
+	return e.TLS.ServerName
+}
+
+func (e *CfgConfig) getGraylogTLSMinVersion() uint16 {
+	return parseTLSVersion(e.TLS.MinVersion)
+}
+
+func (e *CfgConfig) getGraylogTLSCipherSuites() []uint16 {
+	return parseTLSCipherSuites(strings.Join(e.TLS.CipherSuites, ","))
+}
+
+// getSinkConfigs returns e.Sinks when set, or a console+Graylog(+syslog)
+// default list otherwise, so existing CfgConfig users who predate the
+// Sinks field keep their current behavior.
+func (e *CfgConfig) getSinkConfigs() []SinkConfig {
+	if len(e.Sinks) > 0 {
+		return e.Sinks
+	}
+
+	sinks := []SinkConfig{{Driver: "console"}, {Driver: "graylog"}}
+	if e.useSyslog() {
+		sinks = append(sinks, SinkConfig{Driver: "syslog"})
+	}
+
+	return sinks
+}
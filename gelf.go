@@ -0,0 +1,216 @@
+package gzap
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+const (
+	gelfVersion = "1.1"
+
+	// gelfChunkPayload is the max payload per UDP chunk, leaving room for
+	// the 12-byte chunk header under a conservative 8154-byte WAN MTU.
+	gelfChunkPayload = 8142
+	gelfChunkMagic0  = 0x1e
+	gelfChunkMagic1  = 0x0f
+	gelfMaxChunks    = 128
+)
+
+// encodeGELF builds a GELF 1.1 payload (https://go2docs.graylog.org/5-0/...)
+// carrying the mandatory version/host/short_message/timestamp/level
+// fields plus every zap field as an underscore-prefixed additional field.
+func encodeGELF(entry zapcore.Entry, fields []zapcore.Field, cfg Config) ([]byte, error) {
+	host, err := os.Hostname()
+	if err != nil {
+		host = "unknown"
+	}
+
+	msg := map[string]interface{}{
+		"version":       gelfVersion,
+		"host":          host,
+		"short_message": entry.Message,
+		"timestamp":     float64(entry.Time.UnixNano()) / float64(time.Second),
+		"level":         syslogSeverity(entry.Level),
+	}
+
+	if appName := cfg.getGraylogAppName(); appName != "" {
+		msg["_app"] = appName
+	}
+
+	for _, f := range fields {
+		msg["_"+f.Key] = fieldToInterface(f)
+	}
+
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return nil, fmt.Errorf("gzap: could not marshal GELF message: %w", err)
+	}
+
+	return data, nil
+}
+
+// fieldToInterface renders a zap field's value as a plain Go value
+// suitable for json.Marshal, preserving its native type instead of
+// stringifying it.
+func fieldToInterface(f zapcore.Field) interface{} {
+	switch f.Type {
+	case zapcore.StringType:
+		return f.String
+	case zapcore.Int64Type, zapcore.Int32Type, zapcore.Int16Type, zapcore.Int8Type:
+		return f.Integer
+	case zapcore.Uint64Type, zapcore.Uint32Type, zapcore.Uint16Type, zapcore.Uint8Type:
+		return f.Integer
+	case zapcore.Float64Type:
+		return math.Float64frombits(uint64(f.Integer))
+	case zapcore.Float32Type:
+		return math.Float32frombits(uint32(f.Integer))
+	case zapcore.BoolType:
+		return f.Integer == 1
+	default:
+		if f.Interface != nil {
+			return f.Interface
+		}
+		return f.String
+	}
+}
+
+// gzipCompress compresses data the way Graylog's GELF UDP/TCP inputs
+// expect a compressed body to look: a standard gzip stream.
+func gzipCompress(data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	if _, err := w.Write(data); err != nil {
+		return nil, fmt.Errorf("gzap: could not gzip GELF message: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return nil, fmt.Errorf("gzap: could not finalize gzip GELF message: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// chunkGELF splits a compressed GELF payload into Graylog's UDP chunking
+// format (https://go2docs.graylog.org/5-0/...#chunking) when it exceeds
+// gelfChunkPayload; small payloads are returned unchanged and sent as a
+// single plain datagram, which Graylog also accepts.
+func chunkGELF(data []byte) ([][]byte, error) {
+	if len(data) <= gelfChunkPayload {
+		return [][]byte{data}, nil
+	}
+
+	numChunks := (len(data) + gelfChunkPayload - 1) / gelfChunkPayload
+	if numChunks > gelfMaxChunks {
+		return nil, fmt.Errorf("gzap: GELF message too large to chunk (%d chunks, max %d)", numChunks, gelfMaxChunks)
+	}
+
+	var messageID [8]byte
+	if _, err := rand.Read(messageID[:]); err != nil {
+		return nil, fmt.Errorf("gzap: could not generate GELF chunk message id: %w", err)
+	}
+
+	chunks := make([][]byte, 0, numChunks)
+	for i := 0; i < numChunks; i++ {
+		start := i * gelfChunkPayload
+		end := start + gelfChunkPayload
+		if end > len(data) {
+			end = len(data)
+		}
+
+		chunk := make([]byte, 0, 12+end-start)
+		chunk = append(chunk, gelfChunkMagic0, gelfChunkMagic1)
+		chunk = append(chunk, messageID[:]...)
+		chunk = append(chunk, byte(i), byte(numChunks))
+		chunk = append(chunk, data[start:end]...)
+
+		chunks = append(chunks, chunk)
+	}
+
+	return chunks, nil
+}
+
+// graylogCore is a zapcore.Core that encodes each entry as a GELF
+// message and hands it to a BufferedGraylogSink: gzip-compressed and
+// chunked over UDP, or newline^Wnull-byte-delimited and uncompressed
+// over TCP(+TLS), matching what a Graylog GELF input expects on each
+// transport.
+type graylogCore struct {
+	zapcore.LevelEnabler
+	cfg    Config
+	sink   *BufferedGraylogSink
+	fields []zapcore.Field
+}
+
+// newGraylogCore builds the zapcore.Core backing the "graylog" sink
+// driver, framing every entry as a real GELF message instead of raw
+// JSON over the wire.
+func newGraylogCore(cfg Config, sink *BufferedGraylogSink) zapcore.Core {
+	level := zapcore.Level(int8(cfg.getGraylogLogLevel()))
+
+	return &graylogCore{
+		LevelEnabler: level,
+		cfg:          cfg,
+		sink:         sink,
+	}
+}
+
+func (c *graylogCore) With(fields []zapcore.Field) zapcore.Core {
+	return &graylogCore{
+		LevelEnabler: c.LevelEnabler,
+		cfg:          c.cfg,
+		sink:         c.sink,
+		fields:       append(c.fields[:len(c.fields):len(c.fields)], fields...),
+	}
+}
+
+func (c *graylogCore) Check(entry zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(entry.Level) {
+		return ce.AddCore(entry, c)
+	}
+	return ce
+}
+
+func (c *graylogCore) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	all := append(c.fields[:len(c.fields):len(c.fields)], fields...)
+
+	payload, err := encodeGELF(entry, all, c.cfg)
+	if err != nil {
+		return err
+	}
+
+	if c.cfg.useTLS() {
+		// Graylog's TCP GELF input is a null-byte-delimited stream of
+		// uncompressed messages; chunking is UDP-only.
+		framed := append(payload, 0)
+		return c.sink.enqueue(framed)
+	}
+
+	compressed, err := gzipCompress(payload)
+	if err != nil {
+		return err
+	}
+
+	chunks, err := chunkGELF(compressed)
+	if err != nil {
+		return err
+	}
+
+	for _, chunk := range chunks {
+		if err := c.sink.enqueue(chunk); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (c *graylogCore) Sync() error {
+	return c.sink.Sync()
+}
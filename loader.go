@@ -0,0 +1,325 @@
+package gzap
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/fsnotify/fsnotify"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"gopkg.in/yaml.v3"
+)
+
+// Source is one layer in a LoadConfig call. Sources are applied in the
+// order they're passed, each overriding any field it sets on the ones
+// before it; defaults come from NewDefaultCfgConfig.
+type Source interface {
+	apply(cfg *CfgConfig) error
+}
+
+// FileSource loads a YAML, JSON, or TOML file, autodetected from its
+// extension (.yaml/.yml, .json, .toml), and merges it onto the config.
+type FileSource struct {
+	Path string
+}
+
+func (s FileSource) apply(cfg *CfgConfig) error {
+	data, err := os.ReadFile(s.Path)
+	if err != nil {
+		return fmt.Errorf("gzap: could not read config file %q: %w", s.Path, err)
+	}
+
+	switch ext := strings.ToLower(filepath.Ext(s.Path)); ext {
+	case ".yaml", ".yml":
+		if err := yaml.Unmarshal(data, cfg); err != nil {
+			return fmt.Errorf("gzap: could not parse YAML config %q: %w", s.Path, err)
+		}
+	case ".json":
+		if err := json.Unmarshal(data, cfg); err != nil {
+			return fmt.Errorf("gzap: could not parse JSON config %q: %w", s.Path, err)
+		}
+	case ".toml":
+		if err := toml.Unmarshal(data, cfg); err != nil {
+			return fmt.Errorf("gzap: could not parse TOML config %q: %w", s.Path, err)
+		}
+	default:
+		return fmt.Errorf("gzap: unrecognized config file extension %q", ext)
+	}
+
+	return nil
+}
+
+// EnvSource merges the same environment variables EnvConfig reads,
+// without panicking on the ones that are unset. THEMUSE_ENV_LEVEL is not
+// read, since CfgConfig has no field for colored console output.
+type EnvSource struct{}
+
+func (s EnvSource) apply(cfg *CfgConfig) error {
+	if v := os.Getenv("GRAYLOG_APP_NAME"); v != "" {
+		cfg.AppName = v
+	}
+	if v := os.Getenv("GRAYLOG_ENV"); v != "" {
+		cfg.EnvName = v
+	}
+	if v := os.Getenv("GRAYLOG_HANDLER_TYPE"); v != "" {
+		cfg.HanlderType = v
+	}
+	if v := os.Getenv("GRAYLOG_HOST"); v != "" {
+		cfg.Host = v
+	}
+	if v := os.Getenv("GRAYLOG_UDP_PORT"); v != "" {
+		port, err := strconv.ParseUint(v, 10, 32)
+		if err != nil {
+			return fmt.Errorf("gzap: could not parse GRAYLOG_UDP_PORT: %w", err)
+		}
+		cfg.UDPPort = uint(port)
+	}
+	if v := os.Getenv("GRAYLOG_TLS_PORT"); v != "" {
+		port, err := strconv.ParseUint(v, 10, 32)
+		if err != nil {
+			return fmt.Errorf("gzap: could not parse GRAYLOG_TLS_PORT: %w", err)
+		}
+		cfg.TLSPort = uint(port)
+	}
+	if v := os.Getenv("GRAYLOG_TLS_TIMEOUT_SECS"); v != "" {
+		cfg.TLSTimeoutSeconds = v
+	}
+	if v := os.Getenv("GRAYLOG_LOG_LEVEL"); v != "" {
+		level, err := strconv.ParseUint(v, 10, 32)
+		if err != nil {
+			return fmt.Errorf("gzap: could not parse GRAYLOG_LOG_LEVEL: %w", err)
+		}
+		cfg.LogLevel = uint(level)
+	}
+	if v := os.Getenv("ENABLE_DATADOG_JSON_FORMATTER"); v != "" {
+		cfg.EnableJSONFormatter = v == "true"
+	}
+	if v := os.Getenv("GRAYLOG_SKIP_TLS_VERIFY"); v != "" {
+		cfg.TLS.InsecureSkipVerify = v == "true"
+	}
+	if v := os.Getenv("SYSLOG_ADDRESS"); v != "" {
+		cfg.Syslog.Enabled = true
+		cfg.Syslog.Address = v
+	}
+	if v := os.Getenv("SYSLOG_NETWORK"); v != "" {
+		cfg.Syslog.Network = v
+	}
+	if v := os.Getenv("SYSLOG_FACILITY"); v != "" {
+		cfg.Syslog.Facility = v
+	}
+	if v := os.Getenv("SYSLOG_APP_TAG"); v != "" {
+		cfg.Syslog.AppTag = v
+	}
+	if v := os.Getenv("SYSLOG_HOSTNAME"); v != "" {
+		cfg.Syslog.Hostname = v
+	}
+	if v := os.Getenv("SYSLOG_USE_BSD_FRAMING"); v != "" {
+		cfg.Syslog.UseRFC5424 = v != "true"
+	}
+	if v := os.Getenv("SYSLOG_SKIP_TLS_VERIFY"); v != "" {
+		cfg.Syslog.TLS.InsecureSkipVerify = v == "true"
+	}
+	if v := os.Getenv("SYSLOG_TLS_CA_FILE"); v != "" {
+		cfg.Syslog.TLS.CAFile = v
+	}
+	if v := os.Getenv("SYSLOG_TLS_CERT_FILE"); v != "" {
+		cfg.Syslog.TLS.CertFile = v
+	}
+	if v := os.Getenv("SYSLOG_TLS_KEY_FILE"); v != "" {
+		cfg.Syslog.TLS.KeyFile = v
+	}
+	if v := os.Getenv("SYSLOG_TLS_SERVER_NAME"); v != "" {
+		cfg.Syslog.TLS.ServerName = v
+	}
+	if v := os.Getenv("SYSLOG_TLS_MIN_VERSION"); v != "" {
+		cfg.Syslog.TLS.MinVersion = v
+	}
+	if v := os.Getenv("SYSLOG_TLS_CIPHER_SUITES"); v != "" {
+		cfg.Syslog.TLS.CipherSuites = strings.Split(v, ",")
+	}
+	if v := os.Getenv("GRAYLOG_QUEUE_SIZE"); v != "" {
+		size, err := strconv.ParseUint(v, 10, 32)
+		if err != nil {
+			return fmt.Errorf("gzap: could not parse GRAYLOG_QUEUE_SIZE: %w", err)
+		}
+		cfg.QueueSize = uint(size)
+	}
+	if v := os.Getenv("GRAYLOG_SPILL_DIR"); v != "" {
+		cfg.SpillDir = v
+	}
+	if v := os.Getenv("GRAYLOG_SPILL_MAX_BYTES"); v != "" {
+		maxBytes, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return fmt.Errorf("gzap: could not parse GRAYLOG_SPILL_MAX_BYTES: %w", err)
+		}
+		cfg.SpillMaxBytes = maxBytes
+	}
+	if v := os.Getenv("GRAYLOG_TLS_CA_FILE"); v != "" {
+		cfg.TLS.CAFile = v
+	}
+	if v := os.Getenv("GRAYLOG_TLS_CERT_FILE"); v != "" {
+		cfg.TLS.CertFile = v
+	}
+	if v := os.Getenv("GRAYLOG_TLS_KEY_FILE"); v != "" {
+		cfg.TLS.KeyFile = v
+	}
+	if v := os.Getenv("GRAYLOG_TLS_SERVER_NAME"); v != "" {
+		cfg.TLS.ServerName = v
+	}
+	if v := os.Getenv("GRAYLOG_TLS_MIN_VERSION"); v != "" {
+		cfg.TLS.MinVersion = v
+	}
+	if v := os.Getenv("GRAYLOG_TLS_CIPHER_SUITES"); v != "" {
+		cfg.TLS.CipherSuites = strings.Split(v, ",")
+	}
+
+	return nil
+}
+
+// FlagSource merges values parsed onto an already-parsed flag.FlagSet,
+// taking precedence over file and environment sources. Only flags that
+// were actually set on the command line are applied.
+type FlagSource struct {
+	FlagSet *flag.FlagSet
+}
+
+func (s FlagSource) apply(cfg *CfgConfig) error {
+	if s.FlagSet == nil {
+		return nil
+	}
+
+	var applyErr error
+	s.FlagSet.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "graylog-app-name":
+			cfg.AppName = f.Value.String()
+		case "graylog-env":
+			cfg.EnvName = f.Value.String()
+		case "graylog-handler-type":
+			cfg.HanlderType = f.Value.String()
+		case "graylog-host":
+			cfg.Host = f.Value.String()
+		case "graylog-log-level":
+			level, err := strconv.ParseUint(f.Value.String(), 10, 32)
+			if err != nil {
+				applyErr = fmt.Errorf("gzap: could not parse -graylog-log-level: %w", err)
+				return
+			}
+			cfg.LogLevel = uint(level)
+		}
+	})
+
+	return applyErr
+}
+
+// LoadConfig layers defaults, then each source in the order given, onto a
+// CfgConfig and validates the result. Callers typically pass a FileSource,
+// then an EnvSource, then a FlagSource so flags win over env which wins
+// over the file, matching the common precedence for CLI tools.
+func LoadConfig(sources ...Source) (Config, error) {
+	cfg := NewDefaultCfgConfig()
+
+	for _, source := range sources {
+		if err := source.apply(cfg); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := validateCfgConfig(cfg); err != nil {
+		return nil, err
+	}
+
+	return cfg, nil
+}
+
+func validateCfgConfig(cfg *CfgConfig) error {
+	if cfg.AppName == "" {
+		return fmt.Errorf("gzap: app_name must be set")
+	}
+
+	if cfg.EnvName == "" {
+		return fmt.Errorf("gzap: env_name must be set")
+	}
+
+	switch cfg.HanlderType {
+	case "udp", "tls":
+	default:
+		return fmt.Errorf("gzap: handler_name must be \"udp\" or \"tls\", got %q", cfg.HanlderType)
+	}
+
+	if cfg.Host == "" {
+		return fmt.Errorf("gzap: host must be set")
+	}
+
+	if cfg.Syslog.Enabled {
+		switch cfg.Syslog.Network {
+		case "udp", "tcp", "tcp+tls":
+		default:
+			return fmt.Errorf("gzap: syslog.network must be \"udp\", \"tcp\", or \"tcp+tls\", got %q", cfg.Syslog.Network)
+		}
+
+		if cfg.Syslog.Address == "" {
+			return fmt.Errorf("gzap: syslog.address must be set when syslog is enabled")
+		}
+	}
+
+	return nil
+}
+
+// Watch re-reads path whenever it changes on disk and invokes onChange
+// with the newly loaded Config, additionally swapping level so that any
+// logger built with it picks up the new threshold without a restart.
+// It runs until ctx is done or the watch can no longer be serviced.
+func Watch(ctx context.Context, path string, level zap.AtomicLevel, onChange func(Config)) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("gzap: could not start config watcher: %w", err)
+	}
+
+	if err := watcher.Add(filepath.Dir(path)); err != nil {
+		watcher.Close()
+		return fmt.Errorf("gzap: could not watch %q: %w", path, err)
+	}
+
+	go func() {
+		defer watcher.Close()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case event, ok := <-watcher.Events:
+				if !ok {
+					return
+				}
+				if filepath.Clean(event.Name) != filepath.Clean(path) {
+					continue
+				}
+				if event.Op&(fsnotify.Write|fsnotify.Create) == 0 {
+					continue
+				}
+
+				cfg, err := LoadConfig(FileSource{Path: path}, EnvSource{})
+				if err != nil {
+					continue
+				}
+
+				level.SetLevel(zapcore.Level(int8(cfg.getGraylogLogLevel())))
+				onChange(cfg)
+			case _, ok := <-watcher.Errors:
+				if !ok {
+					return
+				}
+			}
+		}
+	}()
+
+	return nil
+}
@@ -0,0 +1,237 @@
+package gzap
+
+import (
+	"context"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestFileSourceDetectsFormatByExtension(t *testing.T) {
+	cases := []struct {
+		name string
+		file string
+		data string
+	}{
+		{"yaml", "cfg.yaml", "app_name: fromyaml\n"},
+		{"yml", "cfg.yml", "app_name: fromyaml\n"},
+		{"json", "cfg.json", `{"app_name": "fromyaml"}`},
+		{"toml", "cfg.toml", "app_name = \"fromyaml\"\n"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), c.file)
+			writeFile(t, path, c.data)
+
+			cfg := NewDefaultCfgConfig()
+			if err := (FileSource{Path: path}).apply(cfg); err != nil {
+				t.Fatalf("apply() error = %v", err)
+			}
+			if cfg.AppName != "fromyaml" {
+				t.Errorf("AppName = %q, want %q", cfg.AppName, "fromyaml")
+			}
+		})
+	}
+}
+
+func TestFileSourceRejectsUnrecognizedExtension(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cfg.ini")
+	writeFile(t, path, "app_name=fromini")
+
+	cfg := NewDefaultCfgConfig()
+	if err := (FileSource{Path: path}).apply(cfg); err == nil {
+		t.Error("apply() error = nil, want error for unrecognized extension")
+	}
+}
+
+func TestFileSourceMissingFile(t *testing.T) {
+	cfg := NewDefaultCfgConfig()
+	err := (FileSource{Path: filepath.Join(t.TempDir(), "missing.yaml")}).apply(cfg)
+	if err == nil {
+		t.Error("apply() error = nil, want error for missing file")
+	}
+}
+
+func TestEnvSourceOverridesDefaults(t *testing.T) {
+	t.Setenv("GRAYLOG_APP_NAME", "envapp")
+	t.Setenv("SYSLOG_ADDRESS", "syslog.internal:514")
+	t.Setenv("SYSLOG_TLS_CIPHER_SUITES", "TLS_AES_128_GCM_SHA256,TLS_AES_256_GCM_SHA384")
+
+	cfg := NewDefaultCfgConfig()
+	if err := (EnvSource{}).apply(cfg); err != nil {
+		t.Fatalf("apply() error = %v", err)
+	}
+
+	if cfg.AppName != "envapp" {
+		t.Errorf("AppName = %q, want %q", cfg.AppName, "envapp")
+	}
+	if !cfg.Syslog.Enabled || cfg.Syslog.Address != "syslog.internal:514" {
+		t.Errorf("Syslog = %+v, want enabled with address syslog.internal:514", cfg.Syslog)
+	}
+	if want := []string{"TLS_AES_128_GCM_SHA256", "TLS_AES_256_GCM_SHA384"}; !equalStringSlices(cfg.Syslog.TLS.CipherSuites, want) {
+		t.Errorf("Syslog.TLS.CipherSuites = %v, want %v", cfg.Syslog.TLS.CipherSuites, want)
+	}
+}
+
+func TestEnvSourceInvalidIntReturnsError(t *testing.T) {
+	t.Setenv("GRAYLOG_UDP_PORT", "not-a-number")
+
+	cfg := NewDefaultCfgConfig()
+	if err := (EnvSource{}).apply(cfg); err == nil {
+		t.Error("apply() error = nil, want error for unparsable GRAYLOG_UDP_PORT")
+	}
+}
+
+func TestFlagSourceOnlyAppliesFlagsThatWereSet(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	appName := fs.String("graylog-app-name", "default", "")
+	fs.String("graylog-host", "default", "")
+	if err := fs.Parse([]string{"-graylog-app-name=flagapp"}); err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	_ = appName
+
+	cfg := NewDefaultCfgConfig()
+	cfg.Host = "originalhost"
+	if err := (FlagSource{FlagSet: fs}).apply(cfg); err != nil {
+		t.Fatalf("apply() error = %v", err)
+	}
+
+	if cfg.AppName != "flagapp" {
+		t.Errorf("AppName = %q, want %q", cfg.AppName, "flagapp")
+	}
+	if cfg.Host != "originalhost" {
+		t.Errorf("Host = %q, want unchanged %q since -graylog-host was not set", cfg.Host, "originalhost")
+	}
+}
+
+func TestFlagSourceNilFlagSetIsNoop(t *testing.T) {
+	cfg := NewDefaultCfgConfig()
+	if err := (FlagSource{}).apply(cfg); err != nil {
+		t.Fatalf("apply() error = %v", err)
+	}
+}
+
+func TestLoadConfigLayersSourcesInOrder(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cfg.yaml")
+	writeFile(t, path, "app_name: fromfile\nenv_name: fromfile\nhandler_name: udp\nhost: 127.0.0.1\n")
+
+	t.Setenv("GRAYLOG_APP_NAME", "fromenv")
+
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.String("graylog-app-name", "default", "")
+	if err := fs.Parse([]string{"-graylog-app-name=fromflag"}); err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	cfg, err := LoadConfig(FileSource{Path: path}, EnvSource{}, FlagSource{FlagSet: fs})
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+
+	if got := cfg.getGraylogAppName(); got != "fromflag" {
+		t.Errorf("getGraylogAppName() = %q, want %q (flags should win over env and file)", got, "fromflag")
+	}
+}
+
+func TestLoadConfigSurfacesValidationErrors(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cfg.yaml")
+	writeFile(t, path, "app_name: \"\"\n")
+
+	if _, err := LoadConfig(FileSource{Path: path}); err == nil {
+		t.Error("LoadConfig() error = nil, want validation error for empty app_name")
+	}
+}
+
+func TestValidateCfgConfig(t *testing.T) {
+	valid := func() *CfgConfig {
+		cfg := NewDefaultCfgConfig()
+		cfg.AppName = "app"
+		cfg.EnvName = "env"
+		cfg.HanlderType = "udp"
+		cfg.Host = "127.0.0.1"
+		return cfg
+	}
+
+	if err := validateCfgConfig(valid()); err != nil {
+		t.Errorf("validateCfgConfig(valid) error = %v, want nil", err)
+	}
+
+	cases := []struct {
+		name   string
+		mutate func(*CfgConfig)
+	}{
+		{"missing app name", func(c *CfgConfig) { c.AppName = "" }},
+		{"missing env name", func(c *CfgConfig) { c.EnvName = "" }},
+		{"bad handler type", func(c *CfgConfig) { c.HanlderType = "carrier-pigeon" }},
+		{"missing host", func(c *CfgConfig) { c.Host = "" }},
+		{"bad syslog network", func(c *CfgConfig) { c.Syslog.Enabled = true; c.Syslog.Network = "carrier-pigeon" }},
+		{"missing syslog address", func(c *CfgConfig) { c.Syslog.Enabled = true; c.Syslog.Network = "udp" }},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			cfg := valid()
+			c.mutate(cfg)
+			if err := validateCfgConfig(cfg); err == nil {
+				t.Errorf("validateCfgConfig() error = nil, want error")
+			}
+		})
+	}
+}
+
+func writeFile(t *testing.T, path, data string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatalf("could not write test file %q: %v", path, err)
+	}
+}
+
+func TestWatchReloadsOnFileChange(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cfg.yaml")
+	writeFile(t, path, "app_name: v1\nenv_name: env\nhandler_name: udp\nhost: 127.0.0.1\n")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	level := zap.NewAtomicLevelAt(zapcore.InfoLevel)
+
+	changed := make(chan Config, 1)
+	if err := Watch(ctx, path, level, func(cfg Config) {
+		changed <- cfg
+	}); err != nil {
+		t.Fatalf("Watch() error = %v", err)
+	}
+
+	writeFile(t, path, "app_name: v2\nenv_name: env\nhandler_name: udp\nhost: 127.0.0.1\nlog_level: 255\n")
+
+	select {
+	case cfg := <-changed:
+		if got := cfg.getGraylogAppName(); got != "v2" {
+			t.Errorf("reloaded AppName = %q, want %q", got, "v2")
+		}
+		if got := level.Level(); got != zapcore.DebugLevel {
+			t.Errorf("level after reload = %v, want %v", got, zapcore.DebugLevel)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Watch did not invoke onChange after file write")
+	}
+}
+
+func equalStringSlices(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,77 @@
+package gzap
+
+import (
+	"crypto/tls"
+	"fmt"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// LoggerOption configures a *zap.Logger built by NewLogger beyond what
+// Config alone can express.
+type LoggerOption func(*loggerOptions)
+
+type loggerOptions struct {
+	tlsConfig *tls.Config
+}
+
+// WithTLSConfig injects a fully-built *tls.Config to use when dialing
+// Graylog over tcp+tls, taking precedence over cfg's TLSConfig knobs.
+// This lets callers wire in certificates issued by SPIFFE, Vault, or any
+// other source that doesn't fit a file-path-based config.
+func WithTLSConfig(tlsConfig *tls.Config) LoggerOption {
+	return func(o *loggerOptions) {
+		o.tlsConfig = tlsConfig
+	}
+}
+
+// buildGraylogCore builds the zapcore.Core backing the "graylog" sink
+// driver: a BufferedGraylogSink fed GELF-framed messages via
+// newGraylogCore, rather than a plain zapcore.NewCore writing raw JSON.
+// tlsConfig overrides cfg's TLSConfig knobs when cfg.useTLS() is true.
+func buildGraylogCore(cfg Config, tlsConfig *tls.Config) (zapcore.Core, error) {
+	sink, err := NewBufferedGraylogSink(cfg, tlsConfig)
+	if err != nil {
+		return nil, fmt.Errorf("gzap: could not build graylog sink: %w", err)
+	}
+
+	return newGraylogCore(cfg, sink), nil
+}
+
+// tlsConfigOverrideProvider is implemented by the Config passed into a
+// SinkFactory when NewLogger was given WithTLSConfig. The "graylog"
+// driver checks for it so an injected *tls.Config takes precedence over
+// cfg's own TLSConfig knobs.
+type tlsConfigOverrideProvider interface {
+	tlsConfigOverride() *tls.Config
+}
+
+type loggerTLSOverrideConfig struct {
+	Config
+	tlsConfig *tls.Config
+}
+
+func (c *loggerTLSOverrideConfig) tlsConfigOverride() *tls.Config {
+	return c.tlsConfig
+}
+
+// NewLogger builds a *zap.Logger that tees every sink cfg registers
+// (console, Graylog, syslog, and any driver added via RegisterSink).
+func NewLogger(cfg Config, opts ...LoggerOption) (*zap.Logger, error) {
+	o := &loggerOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	if o.tlsConfig != nil {
+		cfg = &loggerTLSOverrideConfig{Config: cfg, tlsConfig: o.tlsConfig}
+	}
+
+	cores, err := BuildSinks(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return zap.New(zapcore.NewTee(cores...)), nil
+}
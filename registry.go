@@ -0,0 +1,197 @@
+package gzap
+
+import (
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"go.uber.org/zap/zapcore"
+	"gopkg.in/yaml.v3"
+)
+
+// SinkFactory builds a zapcore.Core for one entry in CfgConfig.Sinks. A
+// factory that needs its driver-specific Options reads them off cfg via
+// sinkOptionsProvider, since cfg is scoped to the SinkConfig being built.
+type SinkFactory func(cfg Config) (zapcore.Core, error)
+
+var (
+	sinkRegistryMu sync.RWMutex
+	sinkRegistry   = map[string]SinkFactory{}
+)
+
+// RegisterSink makes factory available under name for use in
+// CfgConfig.Sinks (or EnvConfig's default sink list). Registering under
+// an existing name replaces it; this is how downstream users add
+// proprietary sinks (HTTP, Kafka, ...) without forking gzap.
+func RegisterSink(name string, factory SinkFactory) {
+	sinkRegistryMu.Lock()
+	defer sinkRegistryMu.Unlock()
+	sinkRegistry[name] = factory
+}
+
+func lookupSink(name string) (SinkFactory, bool) {
+	sinkRegistryMu.RLock()
+	defer sinkRegistryMu.RUnlock()
+	factory, ok := sinkRegistry[name]
+	return factory, ok
+}
+
+func init() {
+	RegisterSink("console", func(cfg Config) (zapcore.Core, error) {
+		return buildConsoleCore(cfg), nil
+	})
+	RegisterSink("graylog", func(cfg Config) (zapcore.Core, error) {
+		var tlsConfig *tls.Config
+		if provider, ok := cfg.(tlsConfigOverrideProvider); ok {
+			tlsConfig = provider.tlsConfigOverride()
+		}
+		return buildGraylogCore(cfg, tlsConfig)
+	})
+	RegisterSink("syslog", NewSyslogCore)
+}
+
+// SinkConfig names one registered sink driver, its own minimum level
+// (independent of CfgConfig.LogLevel), and a driver-specific options
+// blob. A nil MinLevel means "no extra restriction beyond the driver's
+// own level"; it's a pointer rather than a bare int8 so that explicitly
+// configuring zapcore.InfoLevel (which is 0) is distinguishable from
+// leaving it unset.
+type SinkConfig struct {
+	Driver   string      `json:"driver" yaml:"driver" toml:"driver"`
+	MinLevel *int8       `json:"min_level" yaml:"min_level" toml:"min_level"`
+	Options  SinkOptions `json:"options" yaml:"options" toml:"options"`
+}
+
+// SinkOptions holds a sink driver's options as raw JSON, regardless of
+// which format (JSON, YAML, or TOML) the enclosing CfgConfig was loaded
+// from. json.RawMessage only implements json.Unmarshaler, so a nested
+// "sinks[].options" map in a YAML or TOML file would otherwise fail to
+// unmarshal; UnmarshalYAML and UnmarshalTOML decode into a generic value
+// and re-marshal it to JSON so sinkOptionsProvider always hands drivers
+// plain JSON to json.Unmarshal into their own struct.
+type SinkOptions json.RawMessage
+
+func (o SinkOptions) MarshalJSON() ([]byte, error) {
+	if len(o) == 0 {
+		return []byte("null"), nil
+	}
+	return o, nil
+}
+
+func (o *SinkOptions) UnmarshalJSON(data []byte) error {
+	*o = append((*o)[:0], data...)
+	return nil
+}
+
+func (o *SinkOptions) UnmarshalYAML(value *yaml.Node) error {
+	var v interface{}
+	if err := value.Decode(&v); err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("gzap: could not re-encode sink options as JSON: %w", err)
+	}
+
+	*o = data
+	return nil
+}
+
+func (o *SinkOptions) UnmarshalTOML(v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("gzap: could not re-encode sink options as JSON: %w", err)
+	}
+
+	*o = data
+	return nil
+}
+
+// sinkOptionsProvider is implemented by the Config passed to a
+// SinkFactory when it originated from a SinkConfig with a non-empty
+// Options blob. Drivers that take driver-specific options type-assert
+// for it and json.Unmarshal into their own struct.
+type sinkOptionsProvider interface {
+	sinkOptions() json.RawMessage
+}
+
+type sinkScopedConfig struct {
+	Config
+	opts json.RawMessage
+}
+
+func (s *sinkScopedConfig) sinkOptions() json.RawMessage {
+	return s.opts
+}
+
+// tlsConfigOverride forwards to the embedded Config's own override, if
+// it has one. Embedding Config as an interface only promotes the
+// methods declared on the Config interface itself, so without this a
+// tlsConfigOverrideProvider on the concrete Config (e.g.
+// loggerTLSOverrideConfig) would never be visible through a
+// sinkScopedConfig, silently dropping WithTLSConfig whenever a
+// SinkConfig also set Options.
+func (s *sinkScopedConfig) tlsConfigOverride() *tls.Config {
+	if provider, ok := s.Config.(tlsConfigOverrideProvider); ok {
+		return provider.tlsConfigOverride()
+	}
+	return nil
+}
+
+// BuildSinks resolves every entry in cfg.getSinkConfigs() to a
+// zapcore.Core via the registry, applying each entry's own MinLevel on
+// top of whatever level the driver itself enforces.
+func BuildSinks(cfg Config) ([]zapcore.Core, error) {
+	sinkConfigs := cfg.getSinkConfigs()
+
+	cores := make([]zapcore.Core, 0, len(sinkConfigs))
+	for _, sinkConfig := range sinkConfigs {
+		factory, ok := lookupSink(sinkConfig.Driver)
+		if !ok {
+			return nil, fmt.Errorf("gzap: no sink registered for driver %q", sinkConfig.Driver)
+		}
+
+		scopedCfg := Config(cfg)
+		if len(sinkConfig.Options) > 0 {
+			scopedCfg = &sinkScopedConfig{Config: cfg, opts: json.RawMessage(sinkConfig.Options)}
+		}
+
+		core, err := factory(scopedCfg)
+		if err != nil {
+			return nil, fmt.Errorf("gzap: could not build sink %q: %w", sinkConfig.Driver, err)
+		}
+
+		if sinkConfig.MinLevel != nil {
+			core = &leveledCore{Core: core, level: zapcore.Level(*sinkConfig.MinLevel)}
+		}
+
+		cores = append(cores, core)
+	}
+
+	return cores, nil
+}
+
+// leveledCore enforces an additional minimum level on top of whatever
+// level the wrapped core already applies, so a SinkConfig's MinLevel can
+// only narrow (never widen) what the driver itself would emit.
+type leveledCore struct {
+	zapcore.Core
+	level zapcore.LevelEnabler
+}
+
+func (c *leveledCore) Enabled(lvl zapcore.Level) bool {
+	return c.level.Enabled(lvl) && c.Core.Enabled(lvl)
+}
+
+func (c *leveledCore) Check(entry zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(entry.Level) {
+		return ce.AddCore(entry, c)
+	}
+	return ce
+}
+
+func (c *leveledCore) With(fields []zapcore.Field) zapcore.Core {
+	return &leveledCore{Core: c.Core.With(fields), level: c.level}
+}
@@ -0,0 +1,190 @@
+package gzap
+
+import (
+	"crypto/tls"
+	"encoding/json"
+	"testing"
+
+	"github.com/BurntSushi/toml"
+	"go.uber.org/zap/zapcore"
+	"gopkg.in/yaml.v3"
+)
+
+func TestSinkOptionsUnmarshalJSON(t *testing.T) {
+	var o SinkOptions
+	if err := json.Unmarshal([]byte(`{"a":1}`), &o); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if string(o) != `{"a":1}` {
+		t.Errorf("SinkOptions = %s, want %s", o, `{"a":1}`)
+	}
+}
+
+func TestSinkOptionsUnmarshalYAML(t *testing.T) {
+	var cfg struct {
+		Options SinkOptions `yaml:"options"`
+	}
+	if err := yaml.Unmarshal([]byte("options:\n  a: 1\n  b: two\n"), &cfg); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+
+	var decoded map[string]interface{}
+	if err := json.Unmarshal(cfg.Options, &decoded); err != nil {
+		t.Fatalf("re-decoding SinkOptions as JSON failed: %v", err)
+	}
+	if decoded["b"] != "two" {
+		t.Errorf("decoded[\"b\"] = %v, want %q", decoded["b"], "two")
+	}
+}
+
+func TestSinkOptionsUnmarshalTOML(t *testing.T) {
+	var cfg struct {
+		Options SinkOptions `toml:"options"`
+	}
+	if err := toml.Unmarshal([]byte("[options]\na = 1\nb = \"two\"\n"), &cfg); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+
+	var decoded map[string]interface{}
+	if err := json.Unmarshal(cfg.Options, &decoded); err != nil {
+		t.Fatalf("re-decoding SinkOptions as JSON failed: %v", err)
+	}
+	if decoded["b"] != "two" {
+		t.Errorf("decoded[\"b\"] = %v, want %q", decoded["b"], "two")
+	}
+}
+
+func TestSinkOptionsMarshalJSONEmptyIsNull(t *testing.T) {
+	var o SinkOptions
+	data, err := o.MarshalJSON()
+	if err != nil {
+		t.Fatalf("MarshalJSON() error = %v", err)
+	}
+	if string(data) != "null" {
+		t.Errorf("MarshalJSON() = %s, want null", data)
+	}
+}
+
+func TestRegisterAndLookupSink(t *testing.T) {
+	RegisterSink("test-registry-probe", func(cfg Config) (zapcore.Core, error) {
+		return zapcore.NewNopCore(), nil
+	})
+
+	factory, ok := lookupSink("test-registry-probe")
+	if !ok {
+		t.Fatal("lookupSink() ok = false, want true after RegisterSink")
+	}
+	if _, err := factory(NewDefaultCfgConfig()); err != nil {
+		t.Errorf("factory() error = %v", err)
+	}
+
+	if _, ok := lookupSink("does-not-exist"); ok {
+		t.Error("lookupSink() ok = true for an unregistered driver, want false")
+	}
+}
+
+func TestSinkScopedConfigForwardsOptionsAndTLSOverride(t *testing.T) {
+	base := NewDefaultCfgConfig()
+	scoped := &sinkScopedConfig{Config: base, opts: json.RawMessage(`{"x":1}`)}
+
+	if string(scoped.sinkOptions()) != `{"x":1}` {
+		t.Errorf("sinkOptions() = %s, want %s", scoped.sinkOptions(), `{"x":1}`)
+	}
+
+	// base doesn't implement tlsConfigOverrideProvider, so the override
+	// should come back nil rather than panicking on the type assertion.
+	if got := scoped.tlsConfigOverride(); got != nil {
+		t.Errorf("tlsConfigOverride() = %v, want nil", got)
+	}
+
+	override := &tlsOverrideConfig{Config: base, tlsConfig: &tls.Config{ServerName: "overridden"}}
+	scopedWithOverride := &sinkScopedConfig{Config: override, opts: json.RawMessage(`{}`)}
+	got := scopedWithOverride.tlsConfigOverride()
+	if got == nil || got.ServerName != "overridden" {
+		t.Errorf("tlsConfigOverride() = %v, want ServerName=overridden", got)
+	}
+}
+
+type tlsOverrideConfig struct {
+	Config
+	tlsConfig *tls.Config
+}
+
+func (c *tlsOverrideConfig) tlsConfigOverride() *tls.Config {
+	return c.tlsConfig
+}
+
+func TestLeveledCoreNarrowsButNeverWidens(t *testing.T) {
+	inner := zapcore.NewCore(
+		zapcore.NewJSONEncoder(zapcore.EncoderConfig{}),
+		zapcore.AddSync(nopWriter{}),
+		zapcore.WarnLevel,
+	)
+
+	core := &leveledCore{Core: inner, level: zapcore.ErrorLevel}
+
+	if core.Enabled(zapcore.WarnLevel) {
+		t.Error("Enabled(Warn) = true, want false since leveledCore narrows to Error")
+	}
+	if !core.Enabled(zapcore.ErrorLevel) {
+		t.Error("Enabled(Error) = false, want true")
+	}
+
+	// Widening the leveledCore's own level can't unlock what the wrapped
+	// core already rejects.
+	wide := &leveledCore{Core: inner, level: zapcore.DebugLevel}
+	if wide.Enabled(zapcore.InfoLevel) {
+		t.Error("Enabled(Info) = true, want false since the wrapped core is Warn-and-above")
+	}
+}
+
+func TestLeveledCoreWithPreservesLevel(t *testing.T) {
+	inner := zapcore.NewCore(
+		zapcore.NewJSONEncoder(zapcore.EncoderConfig{}),
+		zapcore.AddSync(nopWriter{}),
+		zapcore.DebugLevel,
+	)
+	core := &leveledCore{Core: inner, level: zapcore.WarnLevel}
+
+	withCore := core.With([]zapcore.Field{{Key: "k", Type: zapcore.StringType, String: "v"}}).(*leveledCore)
+	if withCore.level != zapcore.WarnLevel {
+		t.Errorf("level after With() = %v, want %v", withCore.level, zapcore.WarnLevel)
+	}
+}
+
+func TestBuildSinksAppliesMinLevelPointerSemantics(t *testing.T) {
+	cfg := NewDefaultCfgConfig()
+	zero := int8(zapcore.InfoLevel)
+	cfg.Sinks = []SinkConfig{
+		{Driver: "console", MinLevel: nil},
+		{Driver: "console", MinLevel: &zero},
+	}
+
+	cores, err := BuildSinks(cfg)
+	if err != nil {
+		t.Fatalf("BuildSinks() error = %v", err)
+	}
+	if len(cores) != 2 {
+		t.Fatalf("BuildSinks() returned %d cores, want 2", len(cores))
+	}
+
+	if _, ok := cores[0].(*leveledCore); ok {
+		t.Error("cores[0] should not be wrapped in leveledCore when MinLevel is nil")
+	}
+	wrapped, ok := cores[1].(*leveledCore)
+	if !ok {
+		t.Fatal("cores[1] should be wrapped in leveledCore when MinLevel is explicitly set")
+	}
+	if wrapped.level != zapcore.InfoLevel {
+		t.Errorf("wrapped level = %v, want %v", wrapped.level, zapcore.InfoLevel)
+	}
+}
+
+func TestBuildSinksUnknownDriverErrors(t *testing.T) {
+	cfg := NewDefaultCfgConfig()
+	cfg.Sinks = []SinkConfig{{Driver: "does-not-exist"}}
+
+	if _, err := BuildSinks(cfg); err == nil {
+		t.Error("BuildSinks() error = nil, want error for unregistered driver")
+	}
+}
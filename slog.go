@@ -0,0 +1,170 @@
+package gzap
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math"
+	"os"
+	"strings"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// buildConsoleCore builds the JSON or colored console encoder core that
+// backs the "console" sink driver, gated at the configured Graylog log
+// level.
+func buildConsoleCore(cfg Config) zapcore.Core {
+	level := zapcore.Level(int8(cfg.getGraylogLogLevel()))
+
+	encoderCfg := zapcore.EncoderConfig{
+		TimeKey:        "ts",
+		LevelKey:       "level",
+		NameKey:        "logger",
+		CallerKey:      "caller",
+		MessageKey:     "message",
+		StacktraceKey:  "stacktrace",
+		LineEnding:     zapcore.DefaultLineEnding,
+		EncodeTime:     zapcore.ISO8601TimeEncoder,
+		EncodeDuration: zapcore.SecondsDurationEncoder,
+		EncodeCaller:   zapcore.ShortCallerEncoder,
+	}
+
+	var encoder zapcore.Encoder
+	switch {
+	case cfg.enableJSONFormatter():
+		encoderCfg.EncodeLevel = zapcore.LowercaseLevelEncoder
+		encoder = zapcore.NewJSONEncoder(encoderCfg)
+	case cfg.useColoredConsolelogs():
+		encoderCfg.EncodeLevel = zapcore.CapitalColorLevelEncoder
+		encoder = zapcore.NewConsoleEncoder(encoderCfg)
+	default:
+		encoderCfg.EncodeLevel = zapcore.CapitalLevelEncoder
+		encoder = zapcore.NewConsoleEncoder(encoderCfg)
+	}
+
+	return zapcore.NewCore(encoder, zapcore.Lock(os.Stdout), level)
+}
+
+// slogHandler adapts a zapcore.Core to the log/slog.Handler interface so
+// callers can adopt log/slog while still fanning out to every sink gzap
+// knows how to write to.
+type slogHandler struct {
+	core   zapcore.Core
+	groups []string
+}
+
+// NewSlogHandler builds a slog.Handler backed by every sink cfg
+// registers (console, Graylog, syslog, ...), so library authors can
+// adopt log/slog without losing fan-out.
+func NewSlogHandler(cfg Config) slog.Handler {
+	cores, err := BuildSinks(cfg)
+	if err != nil || len(cores) == 0 {
+		return &slogHandler{core: buildConsoleCore(cfg)}
+	}
+
+	return &slogHandler{core: zapcore.NewTee(cores...)}
+}
+
+func (h *slogHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return h.core.Enabled(slogLevelToZap(level))
+}
+
+func (h *slogHandler) Handle(_ context.Context, record slog.Record) error {
+	entry := zapcore.Entry{
+		Level:   slogLevelToZap(record.Level),
+		Time:    record.Time,
+		Message: record.Message,
+	}
+
+	fields := make([]zapcore.Field, 0, record.NumAttrs())
+	record.Attrs(func(attr slog.Attr) bool {
+		fields = append(fields, slogAttrToZapField(attr, h.groups))
+		return true
+	})
+
+	if ce := h.core.Check(entry, nil); ce != nil {
+		ce.Write(fields...)
+	}
+
+	return nil
+}
+
+func (h *slogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+
+	fields := make([]zapcore.Field, 0, len(attrs))
+	for _, attr := range attrs {
+		fields = append(fields, slogAttrToZapField(attr, h.groups))
+	}
+
+	return &slogHandler{
+		core:   h.core.With(fields),
+		groups: h.groups,
+	}
+}
+
+func (h *slogHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+
+	groups := make([]string, len(h.groups)+1)
+	copy(groups, h.groups)
+	groups[len(h.groups)] = name
+
+	return &slogHandler{
+		core:   h.core,
+		groups: groups,
+	}
+}
+
+// slogLevelToZap maps log/slog's four levels onto the nearest zap level.
+func slogLevelToZap(level slog.Level) zapcore.Level {
+	switch {
+	case level < slog.LevelInfo:
+		return zapcore.DebugLevel
+	case level < slog.LevelWarn:
+		return zapcore.InfoLevel
+	case level < slog.LevelError:
+		return zapcore.WarnLevel
+	default:
+		return zapcore.ErrorLevel
+	}
+}
+
+// slogAttrToZapField translates a slog.Attr into a zap field, qualifying
+// its key with any active WithGroup prefixes using dot notation.
+func slogAttrToZapField(attr slog.Attr, groups []string) zapcore.Field {
+	key := attr.Key
+	if len(groups) > 0 {
+		key = strings.Join(append(append([]string{}, groups...), attr.Key), ".")
+	}
+
+	value := attr.Value.Resolve()
+
+	switch value.Kind() {
+	case slog.KindString:
+		return zapcore.Field{Key: key, Type: zapcore.StringType, String: value.String()}
+	case slog.KindInt64:
+		return zapcore.Field{Key: key, Type: zapcore.Int64Type, Integer: value.Int64()}
+	case slog.KindUint64:
+		return zapcore.Field{Key: key, Type: zapcore.Uint64Type, Integer: int64(value.Uint64())}
+	case slog.KindFloat64:
+		return zapcore.Field{Key: key, Type: zapcore.Float64Type, Integer: int64(math.Float64bits(value.Float64()))}
+	case slog.KindBool:
+		b := int64(0)
+		if value.Bool() {
+			b = 1
+		}
+		return zapcore.Field{Key: key, Type: zapcore.BoolType, Integer: b}
+	case slog.KindDuration:
+		return zapcore.Field{Key: key, Type: zapcore.DurationType, Integer: int64(value.Duration())}
+	case slog.KindTime:
+		return zapcore.Field{Key: key, Type: zapcore.TimeType, Integer: value.Time().UnixNano()}
+	default:
+		return zapcore.Field{Key: key, Type: zapcore.StringType, String: fmt.Sprintf("%v", value.Any())}
+	}
+}
@@ -0,0 +1,99 @@
+package gzap
+
+import (
+	"log/slog"
+	"testing"
+
+	"go.uber.org/zap/zapcore"
+)
+
+func TestSlogLevelToZap(t *testing.T) {
+	cases := []struct {
+		level slog.Level
+		want  zapcore.Level
+	}{
+		{slog.LevelDebug, zapcore.DebugLevel},
+		{slog.LevelInfo, zapcore.InfoLevel},
+		{slog.LevelWarn, zapcore.WarnLevel},
+		{slog.LevelError, zapcore.ErrorLevel},
+		{slog.LevelError + 4, zapcore.ErrorLevel},
+	}
+
+	for _, c := range cases {
+		if got := slogLevelToZap(c.level); got != c.want {
+			t.Errorf("slogLevelToZap(%v) = %v, want %v", c.level, got, c.want)
+		}
+	}
+}
+
+func TestSlogAttrToZapFieldKinds(t *testing.T) {
+	cases := []struct {
+		name string
+		attr slog.Attr
+		want zapcore.Field
+	}{
+		{"string", slog.String("k", "v"), zapcore.Field{Key: "k", Type: zapcore.StringType, String: "v"}},
+		{"int64", slog.Int64("k", -7), zapcore.Field{Key: "k", Type: zapcore.Int64Type, Integer: -7}},
+		{"bool true", slog.Bool("k", true), zapcore.Field{Key: "k", Type: zapcore.BoolType, Integer: 1}},
+		{"bool false", slog.Bool("k", false), zapcore.Field{Key: "k", Type: zapcore.BoolType, Integer: 0}},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := slogAttrToZapField(c.attr, nil); got != c.want {
+				t.Errorf("slogAttrToZapField(%v) = %+v, want %+v", c.attr, got, c.want)
+			}
+		})
+	}
+}
+
+func TestSlogAttrToZapFieldQualifiesKeyWithGroups(t *testing.T) {
+	got := slogAttrToZapField(slog.String("k", "v"), []string{"a", "b"})
+	if got.Key != "a.b.k" {
+		t.Errorf("Key = %q, want %q", got.Key, "a.b.k")
+	}
+}
+
+func TestSlogHandlerWithGroupQualifiesLaterAttrs(t *testing.T) {
+	h := &slogHandler{core: zapcore.NewNopCore()}
+
+	grouped := h.WithGroup("req").(*slogHandler)
+	if len(grouped.groups) != 1 || grouped.groups[0] != "req" {
+		t.Fatalf("groups = %v, want [req]", grouped.groups)
+	}
+	if got := slogAttrToZapField(slog.String("k", "v"), grouped.groups); got.Key != "req.k" {
+		t.Errorf("Key = %q, want %q", got.Key, "req.k")
+	}
+
+	// WithGroup("") is a documented no-op per the slog.Handler contract.
+	if grouped.WithGroup("") != slog.Handler(grouped) {
+		t.Error("WithGroup(\"\") should return the receiver unchanged")
+	}
+}
+
+func TestSlogHandlerWithAttrsNoopOnEmpty(t *testing.T) {
+	h := &slogHandler{core: zapcore.NewNopCore()}
+
+	if h.WithAttrs(nil) != slog.Handler(h) {
+		t.Error("WithAttrs(nil) should return the receiver unchanged")
+	}
+}
+
+func TestSlogHandlerEnabledFollowsCoreLevel(t *testing.T) {
+	h := &slogHandler{core: zapcore.NewCore(
+		zapcore.NewJSONEncoder(zapcore.EncoderConfig{}),
+		zapcore.AddSync(nopWriter{}),
+		zapcore.WarnLevel,
+	)}
+
+	if h.Enabled(nil, slog.LevelInfo) {
+		t.Error("Enabled(Info) = true, want false below the core's WarnLevel")
+	}
+	if !h.Enabled(nil, slog.LevelError) {
+		t.Error("Enabled(Error) = false, want true")
+	}
+}
+
+type nopWriter struct{}
+
+func (nopWriter) Write(p []byte) (int, error) { return len(p), nil }
@@ -0,0 +1,312 @@
+package gzap
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"math"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// Syslog facility codes, as defined by RFC 5424 section 6.2.1.
+const (
+	facilityKern   = 0
+	facilityUser   = 1
+	facilityMail   = 2
+	facilityDaemon = 3
+	facilityAuth   = 4
+	facilityLocal0 = 16
+	facilityLocal1 = 17
+	facilityLocal2 = 18
+	facilityLocal3 = 19
+	facilityLocal4 = 20
+	facilityLocal5 = 21
+	facilityLocal6 = 22
+	facilityLocal7 = 23
+)
+
+var facilityCodes = map[string]int{
+	"kern":   facilityKern,
+	"user":   facilityUser,
+	"mail":   facilityMail,
+	"daemon": facilityDaemon,
+	"auth":   facilityAuth,
+	"local0": facilityLocal0,
+	"local1": facilityLocal1,
+	"local2": facilityLocal2,
+	"local3": facilityLocal3,
+	"local4": facilityLocal4,
+	"local5": facilityLocal5,
+	"local6": facilityLocal6,
+	"local7": facilityLocal7,
+}
+
+// Syslog severity codes, as defined by RFC 5424 section 6.2.1.
+const (
+	severityAlert   = 1
+	severityCrit    = 2
+	severityErr     = 3
+	severityWarning = 4
+	severityInfo    = 6
+	severityDebug   = 7
+)
+
+// syslogSeverity maps a zap level to the syslog severity it should be
+// reported as: Debug->DEBUG, Info->INFO, Warn->WARNING, Error->ERR,
+// DPanic/Panic->CRIT and Fatal->ALERT.
+func syslogSeverity(lvl zapcore.Level) int {
+	switch lvl {
+	case zapcore.DebugLevel:
+		return severityDebug
+	case zapcore.InfoLevel:
+		return severityInfo
+	case zapcore.WarnLevel:
+		return severityWarning
+	case zapcore.ErrorLevel:
+		return severityErr
+	case zapcore.DPanicLevel, zapcore.PanicLevel:
+		return severityCrit
+	case zapcore.FatalLevel:
+		return severityAlert
+	default:
+		return severityInfo
+	}
+}
+
+// syslogConn wraps a net.Conn and transparently reconnects with an
+// exponential backoff whenever a write fails.
+type syslogConn struct {
+	mu          sync.Mutex
+	network     string
+	address     string
+	tlsConfig   *tls.Config
+	dialTimeout time.Duration
+	conn        net.Conn
+	backoff     time.Duration
+}
+
+const (
+	syslogMinBackoff = 100 * time.Millisecond
+	syslogMaxBackoff = 30 * time.Second
+)
+
+func newSyslogConn(network, address string, tlsConfig *tls.Config, dialTimeout time.Duration) *syslogConn {
+	return &syslogConn{
+		network:     network,
+		address:     address,
+		tlsConfig:   tlsConfig,
+		dialTimeout: dialTimeout,
+		backoff:     syslogMinBackoff,
+	}
+}
+
+func (c *syslogConn) dial() (net.Conn, error) {
+	dialer := &net.Dialer{Timeout: c.dialTimeout}
+
+	if c.network == "tcp+tls" {
+		return tls.DialWithDialer(dialer, "tcp", c.address, c.tlsConfig)
+	}
+
+	return dialer.Dial(c.network, c.address)
+}
+
+func (c *syslogConn) Write(p []byte) (int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.conn == nil {
+		conn, err := c.dial()
+		if err != nil {
+			time.Sleep(c.backoff)
+			c.backoff = nextSyslogBackoff(c.backoff)
+			return 0, fmt.Errorf("gzap: could not dial syslog endpoint: %w", err)
+		}
+		c.conn = conn
+		c.backoff = syslogMinBackoff
+	}
+
+	n, err := c.conn.Write(p)
+	if err != nil {
+		c.conn.Close()
+		c.conn = nil
+		time.Sleep(c.backoff)
+		c.backoff = nextSyslogBackoff(c.backoff)
+		return n, fmt.Errorf("gzap: syslog write failed, will reconnect: %w", err)
+	}
+
+	return n, nil
+}
+
+func (c *syslogConn) Sync() error {
+	return nil
+}
+
+func nextSyslogBackoff(cur time.Duration) time.Duration {
+	next := cur * 2
+	if next > syslogMaxBackoff {
+		return syslogMaxBackoff
+	}
+	return next
+}
+
+// syslogEncoder builds the RFC 5424 (or BSD, RFC 3164) header that is
+// prepended to every entry written by a syslogCore.
+type syslogEncoder struct {
+	facility   int
+	appTag     string
+	hostname   string
+	useRFC5424 bool
+}
+
+func newSyslogEncoder(cfg Config) *syslogEncoder {
+	facility, ok := facilityCodes[cfg.getSyslogFacility()]
+	if !ok {
+		facility = facilityUser
+	}
+
+	return &syslogEncoder{
+		facility:   facility,
+		appTag:     cfg.getSyslogAppTag(),
+		hostname:   cfg.getSyslogHostname(),
+		useRFC5424: cfg.getSyslogUseRFC5424(),
+	}
+}
+
+// priority computes the PRI part of the syslog header: facility*8 + severity.
+func (e *syslogEncoder) priority(lvl zapcore.Level) int {
+	return e.facility*8 + syslogSeverity(lvl)
+}
+
+func (e *syslogEncoder) header(entry zapcore.Entry) string {
+	pri := e.priority(entry.Level)
+
+	if e.useRFC5424 {
+		// <PRI>VERSION TIMESTAMP HOSTNAME APP-NAME PROCID MSGID
+		return fmt.Sprintf("<%d>1 %s %s %s - - ", pri, entry.Time.UTC().Format(time.RFC3339), e.hostname, e.appTag)
+	}
+
+	// BSD framing (RFC 3164): <PRI>TIMESTAMP HOSTNAME TAG:
+	return fmt.Sprintf("<%d>%s %s %s: ", pri, entry.Time.UTC().Format(time.Stamp), e.hostname, e.appTag)
+}
+
+// NewSyslogCore builds a zapcore.Core that forwards entries to a syslog
+// collector (rsyslog, journald, or any RFC 5424 compatible endpoint) over
+// UDP, TCP, or TCP+TLS, reconnecting with backoff on transport errors.
+func NewSyslogCore(cfg Config) (zapcore.Core, error) {
+	network := strings.ToLower(cfg.getSyslogNetwork())
+	if network != "udp" && network != "tcp" && network != "tcp+tls" {
+		return nil, fmt.Errorf("gzap: unsupported syslog network %q", network)
+	}
+
+	address := cfg.getSyslogAddress()
+	if address == "" {
+		return nil, errors.New("gzap: syslog address must be set")
+	}
+
+	dialNetwork := network
+	var tlsConfig *tls.Config
+	if network == "tcp+tls" {
+		dialNetwork = "tcp+tls"
+		built, err := buildSyslogTLSConfig(cfg)
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig = built
+	}
+
+	conn := newSyslogConn(dialNetwork, address, tlsConfig, cfg.getGraylogTLSTimeout())
+
+	level := zapcore.Level(int8(cfg.getGraylogLogLevel()))
+
+	return &syslogCore{
+		LevelEnabler: level,
+		encoder:      newSyslogEncoder(cfg),
+		writer:       conn,
+	}, nil
+}
+
+// syslogCore is a zapcore.Core that writes the RFC 5424/3164 header
+// followed by the structured data elements built from the zap fields.
+type syslogCore struct {
+	zapcore.LevelEnabler
+	encoder *syslogEncoder
+	writer  *syslogConn
+	fields  []zapcore.Field
+}
+
+func (c *syslogCore) With(fields []zapcore.Field) zapcore.Core {
+	return &syslogCore{
+		LevelEnabler: c.LevelEnabler,
+		encoder:      c.encoder,
+		writer:       c.writer,
+		fields:       append(c.fields[:len(c.fields):len(c.fields)], fields...),
+	}
+}
+
+func (c *syslogCore) Check(entry zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(entry.Level) {
+		return ce.AddCore(entry, c)
+	}
+	return ce
+}
+
+func (c *syslogCore) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	all := append(c.fields[:len(c.fields):len(c.fields)], fields...)
+
+	// Structured data elements: "[gzap@1 key="value" ...]" followed by the message.
+	var sd strings.Builder
+	sd.WriteString("[gzap@1")
+	for _, f := range all {
+		fmt.Fprintf(&sd, ` %s="%s"`, f.Key, escapeSDParamValue(fieldToString(f)))
+	}
+	sd.WriteString("]")
+
+	line := c.encoder.header(entry) + sd.String() + " " + entry.Message + "\n"
+
+	_, err := c.writer.Write([]byte(line))
+	return err
+}
+
+func (c *syslogCore) Sync() error {
+	return c.writer.Sync()
+}
+
+// escapeSDParamValue backslash-escapes '"', '\', and ']' per RFC 5424
+// section 6.3.3, so a field value can never prematurely close the
+// SD-ELEMENT or its PARAM-VALUE.
+func escapeSDParamValue(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch r {
+		case '\\', '"', ']':
+			b.WriteByte('\\')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// fieldToString renders a zap field's value for inclusion in an RFC 5424
+// structured data element, without pulling in the full encoder machinery.
+func fieldToString(f zapcore.Field) string {
+	switch f.Type {
+	case zapcore.StringType:
+		return f.String
+	case zapcore.Int64Type, zapcore.Int32Type, zapcore.Int16Type, zapcore.Int8Type,
+		zapcore.Uint64Type, zapcore.Uint32Type, zapcore.Uint16Type, zapcore.Uint8Type:
+		return fmt.Sprintf("%d", f.Integer)
+	case zapcore.Float64Type:
+		return fmt.Sprintf("%v", math.Float64frombits(uint64(f.Integer)))
+	case zapcore.Float32Type:
+		return fmt.Sprintf("%v", math.Float32frombits(uint32(f.Integer)))
+	case zapcore.BoolType:
+		return fmt.Sprintf("%t", f.Integer == 1)
+	default:
+		return fmt.Sprintf("%v", f.Interface)
+	}
+}
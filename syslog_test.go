@@ -0,0 +1,82 @@
+package gzap
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+func TestSyslogSeverity(t *testing.T) {
+	cases := []struct {
+		level zapcore.Level
+		want  int
+	}{
+		{zapcore.DebugLevel, severityDebug},
+		{zapcore.InfoLevel, severityInfo},
+		{zapcore.WarnLevel, severityWarning},
+		{zapcore.ErrorLevel, severityErr},
+		{zapcore.DPanicLevel, severityCrit},
+		{zapcore.PanicLevel, severityCrit},
+		{zapcore.FatalLevel, severityAlert},
+	}
+
+	for _, c := range cases {
+		if got := syslogSeverity(c.level); got != c.want {
+			t.Errorf("syslogSeverity(%v) = %d, want %d", c.level, got, c.want)
+		}
+	}
+}
+
+func TestSyslogEncoderPriority(t *testing.T) {
+	e := &syslogEncoder{facility: facilityLocal0}
+
+	if got, want := e.priority(zapcore.ErrorLevel), facilityLocal0*8+severityErr; got != want {
+		t.Errorf("priority(Error) = %d, want %d", got, want)
+	}
+}
+
+func TestSyslogEncoderHeaderRFC5424(t *testing.T) {
+	e := &syslogEncoder{
+		facility:   facilityUser,
+		appTag:     "myapp",
+		hostname:   "myhost",
+		useRFC5424: true,
+	}
+
+	entry := zapcore.Entry{Level: zapcore.InfoLevel, Time: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)}
+	header := e.header(entry)
+
+	wantPri := facilityUser*8 + severityInfo
+	if want := "<" + strconv.Itoa(wantPri) + ">1 2024-01-02T03:04:05Z myhost myapp - - "; header != want {
+		t.Errorf("header = %q, want %q", header, want)
+	}
+
+	// RFC 5424 only has two fixed dashes (PROCID, MSGID) between APP-NAME
+	// and STRUCTURED-DATA; a third would misalign the structured data
+	// element for strict parsers like rsyslog and journald.
+	if strings.Contains(header, "- - - ") {
+		t.Errorf("header has an extra dash before structured data: %q", header)
+	}
+}
+
+func TestSyslogEncoderHeaderBSD(t *testing.T) {
+	e := &syslogEncoder{
+		facility:   facilityUser,
+		appTag:     "myapp",
+		hostname:   "myhost",
+		useRFC5424: false,
+	}
+
+	entry := zapcore.Entry{Level: zapcore.InfoLevel, Time: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)}
+	header := e.header(entry)
+
+	if !strings.HasSuffix(header, "myhost myapp: ") {
+		t.Errorf("header = %q, want it to end with %q", header, "myhost myapp: ")
+	}
+	if strings.Contains(header, "1 ") {
+		t.Errorf("BSD header should not carry the RFC 5424 VERSION field: %q", header)
+	}
+}
@@ -0,0 +1,120 @@
+package gzap
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"strings"
+)
+
+var tlsVersionsByName = map[string]uint16{
+	"1.0": tls.VersionTLS10,
+	"1.1": tls.VersionTLS11,
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+// parseTLSVersion maps a "1.0".."1.3" string onto the corresponding
+// crypto/tls version constant, defaulting to TLS 1.2 when unset or
+// unrecognized.
+func parseTLSVersion(version string) uint16 {
+	if v, ok := tlsVersionsByName[version]; ok {
+		return v
+	}
+
+	return tls.VersionTLS12
+}
+
+var tlsCipherSuitesByName = func() map[string]uint16 {
+	suites := make(map[string]uint16)
+	for _, suite := range tls.CipherSuites() {
+		suites[suite.Name] = suite.ID
+	}
+	return suites
+}()
+
+// parseTLSCipherSuites turns a comma-separated list of Go cipher suite
+// names (e.g. "TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256") into their IDs,
+// silently skipping any name it doesn't recognize.
+func parseTLSCipherSuites(csv string) []uint16 {
+	if csv == "" {
+		return nil
+	}
+
+	var suites []uint16
+	for _, name := range strings.Split(csv, ",") {
+		name = strings.TrimSpace(name)
+		if id, ok := tlsCipherSuitesByName[name]; ok {
+			suites = append(suites, id)
+		}
+	}
+
+	return suites
+}
+
+// buildGraylogTLSConfig assembles a *tls.Config for dialing Graylog from
+// the TLSConfig knobs on cfg: a custom CA bundle, a client certificate
+// for mTLS, SNI, minimum version, and cipher suite restrictions.
+func buildGraylogTLSConfig(cfg Config) (*tls.Config, error) {
+	return buildTLSConfig(
+		cfg.getGraylogTLSServerName(),
+		cfg.getGraylogTLSMinVersion(),
+		cfg.getGraylogTLSCipherSuites(),
+		cfg.getGraylogSkipInsecureSkipVerify(),
+		cfg.getGraylogTLSCAFile(),
+		cfg.getGraylogTLSCertFile(),
+		cfg.getGraylogTLSKeyFile(),
+	)
+}
+
+// buildSyslogTLSConfig assembles a *tls.Config for dialing a tcp+tls
+// syslog endpoint from its own TLSConfig knobs, independent of Graylog's
+// (a custom CA bundle, a client certificate for mTLS, SNI, minimum
+// version, and cipher suite restrictions).
+func buildSyslogTLSConfig(cfg Config) (*tls.Config, error) {
+	return buildTLSConfig(
+		cfg.getSyslogTLSServerName(),
+		cfg.getSyslogTLSMinVersion(),
+		cfg.getSyslogTLSCipherSuites(),
+		cfg.getSyslogSkipInsecureSkipVerify(),
+		cfg.getSyslogTLSCAFile(),
+		cfg.getSyslogTLSCertFile(),
+		cfg.getSyslogTLSKeyFile(),
+	)
+}
+
+// buildTLSConfig assembles a *tls.Config from the knobs shared by every
+// TLS-dialing sink: SNI, minimum version, cipher suite restrictions, a
+// custom CA bundle, and an optional client certificate for mTLS.
+func buildTLSConfig(serverName string, minVersion uint16, cipherSuites []uint16, insecureSkipVerify bool, caFile, certFile, keyFile string) (*tls.Config, error) {
+	tlsConfig := &tls.Config{
+		ServerName:         serverName,
+		MinVersion:         minVersion,
+		CipherSuites:       cipherSuites,
+		InsecureSkipVerify: insecureSkipVerify,
+	}
+
+	if caFile != "" {
+		pem, err := os.ReadFile(caFile)
+		if err != nil {
+			return nil, fmt.Errorf("gzap: could not read TLS CA file %q: %w", caFile, err)
+		}
+
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("gzap: no certificates found in TLS CA file %q", caFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	if certFile != "" && keyFile != "" {
+		cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+		if err != nil {
+			return nil, fmt.Errorf("gzap: could not load TLS client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	return tlsConfig, nil
+}
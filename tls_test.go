@@ -0,0 +1,111 @@
+package gzap
+
+import (
+	"crypto/tls"
+	"testing"
+)
+
+func TestParseTLSVersion(t *testing.T) {
+	cases := []struct {
+		version string
+		want    uint16
+	}{
+		{"1.0", tls.VersionTLS10},
+		{"1.1", tls.VersionTLS11},
+		{"1.2", tls.VersionTLS12},
+		{"1.3", tls.VersionTLS13},
+		{"", tls.VersionTLS12},
+		{"bogus", tls.VersionTLS12},
+	}
+
+	for _, c := range cases {
+		if got := parseTLSVersion(c.version); got != c.want {
+			t.Errorf("parseTLSVersion(%q) = %#x, want %#x", c.version, got, c.want)
+		}
+	}
+}
+
+func TestParseTLSCipherSuites(t *testing.T) {
+	suites := parseTLSCipherSuites("TLS_AES_128_GCM_SHA256, TLS_AES_256_GCM_SHA384,bogus")
+	want := []uint16{tls.TLS_AES_128_GCM_SHA256, tls.TLS_AES_256_GCM_SHA384}
+
+	if len(suites) != len(want) {
+		t.Fatalf("parseTLSCipherSuites() = %v, want %v", suites, want)
+	}
+	for i := range want {
+		if suites[i] != want[i] {
+			t.Errorf("suite[%d] = %#x, want %#x", i, suites[i], want[i])
+		}
+	}
+}
+
+func TestParseTLSCipherSuitesEmpty(t *testing.T) {
+	if got := parseTLSCipherSuites(""); got != nil {
+		t.Errorf("parseTLSCipherSuites(\"\") = %v, want nil", got)
+	}
+}
+
+func TestBuildTLSConfigBasics(t *testing.T) {
+	cfg, err := buildTLSConfig("example.com", tls.VersionTLS13, []uint16{tls.TLS_AES_128_GCM_SHA256}, true, "", "", "")
+	if err != nil {
+		t.Fatalf("buildTLSConfig() error = %v", err)
+	}
+
+	if cfg.ServerName != "example.com" {
+		t.Errorf("ServerName = %q, want %q", cfg.ServerName, "example.com")
+	}
+	if cfg.MinVersion != tls.VersionTLS13 {
+		t.Errorf("MinVersion = %#x, want %#x", cfg.MinVersion, tls.VersionTLS13)
+	}
+	if !cfg.InsecureSkipVerify {
+		t.Error("InsecureSkipVerify = false, want true")
+	}
+	if cfg.RootCAs != nil {
+		t.Error("RootCAs should be nil when no CA file is given")
+	}
+	if len(cfg.Certificates) != 0 {
+		t.Error("Certificates should be empty when no cert/key file is given")
+	}
+}
+
+func TestBuildTLSConfigMissingCAFile(t *testing.T) {
+	if _, err := buildTLSConfig("", tls.VersionTLS12, nil, false, "/nonexistent/ca.pem", "", ""); err == nil {
+		t.Error("buildTLSConfig() error = nil, want error for unreadable CA file")
+	}
+}
+
+func TestBuildTLSConfigMissingCertFile(t *testing.T) {
+	if _, err := buildTLSConfig("", tls.VersionTLS12, nil, false, "", "/nonexistent/cert.pem", "/nonexistent/key.pem"); err == nil {
+		t.Error("buildTLSConfig() error = nil, want error for unreadable client certificate")
+	}
+}
+
+func TestGraylogAndSyslogTLSConfigsAreIndependent(t *testing.T) {
+	cfg := NewDefaultCfgConfig()
+	cfg.Host = "graylog.internal"
+	cfg.TLS.InsecureSkipVerify = true
+	cfg.Syslog.Address = "syslog.internal:6514"
+	cfg.Syslog.TLS.InsecureSkipVerify = false
+
+	graylogTLS, err := buildGraylogTLSConfig(cfg)
+	if err != nil {
+		t.Fatalf("buildGraylogTLSConfig() error = %v", err)
+	}
+	syslogTLS, err := buildSyslogTLSConfig(cfg)
+	if err != nil {
+		t.Fatalf("buildSyslogTLSConfig() error = %v", err)
+	}
+
+	if !graylogTLS.InsecureSkipVerify {
+		t.Error("graylog InsecureSkipVerify = false, want true")
+	}
+	if syslogTLS.InsecureSkipVerify {
+		t.Error("syslog InsecureSkipVerify = true, want false (independent of graylog's setting)")
+	}
+	if graylogTLS.ServerName != "graylog.internal" {
+		t.Errorf("graylog ServerName = %q, want %q", graylogTLS.ServerName, "graylog.internal")
+	}
+	if syslogTLS.ServerName != "syslog.internal" {
+		t.Errorf("syslog ServerName = %q, want %q", syslogTLS.ServerName, "syslog.internal")
+	}
+}